@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"io"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/baseline"
+	"github.com/benchttp/cli/internal/output"
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/reportfile"
+)
+
+// readBaseline reads the baseline report of flag -baseline, if set,
+// before the benchmark runs. A missing baseline is not an error with
+// -updateBaseline, as it is about to be written.
+func (cmd *cmdRun) readBaseline() error {
+	if cmd.baselineFile == "" {
+		return nil
+	}
+
+	report, _, err := reportfile.Read(cmd.baselineFile)
+	if err != nil {
+		if cmd.updateBaseline && errors.Is(err, reportfile.ErrFileNotFound) {
+			return nil
+		}
+		return err
+	}
+	cmd.baseline = report
+	return nil
+}
+
+// compareBaseline renders the comparison of the report of the benchmark
+// with the baseline, if any, even in silent mode if a metric regressed
+// beyond the tolerance of its config file. It returns an error if so,
+// unless the baseline is being updated.
+func (cmd *cmdRun) compareBaseline(w io.Writer, bench benchmark, report *runner.Report) error {
+	if cmd.baseline == nil {
+		return nil
+	}
+
+	metrics := baseline.Compare(cmd.baseline, report, bench.tolerances)
+	regressed := baseline.Regressed(metrics) && !cmd.updateBaseline
+
+	rendered := make([]render.BaselineMetric, len(metrics))
+	for i, m := range metrics {
+		rendered[i] = render.BaselineMetric{
+			Name:      string(m.Field),
+			Baseline:  m.Baseline,
+			Current:   m.Current,
+			Regressed: m.Regressed,
+		}
+		if m.Tolerance != nil {
+			rendered[i].Tolerance = m.Tolerance.String()
+		}
+	}
+
+	writeIfNotSilent := output.ConditionalWriter{Writer: w}.If(!cmd.silent)
+	if _, err := render.BaselineComparison(
+		writeIfNotSilent.ElseIf(regressed),
+		cmd.baselineFile, rendered,
+	); err != nil {
+		return err
+	}
+
+	if regressed {
+		return errors.New("regression from baseline")
+	}
+	return nil
+}
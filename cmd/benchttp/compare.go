@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/reportfile"
+	"github.com/benchttp/cli/internal/stats"
+)
+
+// compareTests lists the statistical tests of command compare.
+var compareTests = []string{"mannwhitney", "bootstrap"}
+
+// bootstrapIterations is the number of resamples of the bootstrap test.
+const bootstrapIterations = 2000
+
+// cmdCompare handles subcommand "benchttp compare [options] <before> <after>".
+type cmdCompare struct {
+	flagset *flag.FlagSet
+
+	// test is the parsed value for flag -test
+	test string
+
+	// confidence is the parsed value for flag -confidence
+	confidence float64
+
+	// percentile is the parsed value for flag -percentile
+	percentile float64
+}
+
+// bindFlags binds the flags of the command to the flagset.
+func (cmd *cmdCompare) bindFlags() {
+	cmd.flagset.StringVar(&cmd.test,
+		"test",
+		"mannwhitney",
+		"Statistical test: mannwhitney or bootstrap",
+	)
+	cmd.flagset.Float64Var(&cmd.confidence,
+		"confidence",
+		0.95,
+		"Confidence level of the test, between 0 and 1",
+	)
+	cmd.flagset.Float64Var(&cmd.percentile,
+		"percentile",
+		99,
+		"Percentile of the response times compared by the bootstrap test",
+	)
+}
+
+// execute compares the response times of the reports of the given
+// files, as written by benchttp run -report. It fails if the second one
+// is significantly slower.
+func (cmd *cmdCompare) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	if err := cmd.validate(); err != nil {
+		return err
+	}
+
+	beforeFile, afterFile := cmd.flagset.Arg(0), cmd.flagset.Arg(1)
+	before, _, err := reportfile.Read(beforeFile)
+	if err != nil {
+		return err
+	}
+	after, _, err := reportfile.Read(afterFile)
+	if err != nil {
+		return err
+	}
+
+	a, b := stats.ResponseTimes(before), stats.ResponseTimes(after)
+	if len(a) == 0 || len(b) == 0 {
+		return errors.New("cannot compare reports without records")
+	}
+
+	comparison := render.Comparison{
+		Confidence: cmd.confidence,
+		Before:     beforeFile,
+		After:      afterFile,
+		Metrics: []render.ComparedMetric{
+			{Name: "Mean", Before: before.Metrics.ResponseTimes.Mean, After: after.Metrics.ResponseTimes.Mean},
+			{Name: "p50", Before: stats.Percentile(a, 50), After: stats.Percentile(b, 50)},
+			{Name: "p90", Before: stats.Percentile(a, 90), After: stats.Percentile(b, 90)},
+			{Name: "p99", Before: stats.Percentile(a, 99), After: stats.Percentile(b, 99)},
+		},
+	}
+
+	comparison.Method, comparison.Result, comparison.Verdict = cmd.runTest(a, b)
+
+	if _, err := render.ComparisonReport(os.Stdout, comparison); err != nil {
+		return err
+	}
+
+	if comparison.Verdict == render.Regression {
+		return errors.New("significant regression")
+	}
+	return nil
+}
+
+// validate returns an error if the arguments or flags are invalid.
+func (cmd *cmdCompare) validate() error {
+	switch {
+	case cmd.flagset.NArg() != 2:
+		return fmt.Errorf("%w: expected two report files", errUsage)
+	case !contains(compareTests, cmd.test):
+		return fmt.Errorf("%w: unknown test: %s", errUsage, cmd.test)
+	case cmd.confidence <= 0 || cmd.confidence >= 1:
+		return fmt.Errorf("%w: -confidence must be between 0 and 1", errUsage)
+	case cmd.percentile <= 0 || cmd.percentile > 100:
+		return fmt.Errorf("%w: -percentile must be between 0 and 100", errUsage)
+	}
+	return nil
+}
+
+// runTest runs the statistical test of flag -test on the response times
+// a and b, and returns its name, its outcome and its verdict.
+func (cmd *cmdCompare) runTest(a, b []time.Duration) (method, result string, v render.Verdict) {
+	if cmd.test == "bootstrap" {
+		low, high := stats.BootstrapPercentile(a, b, cmd.percentile, cmd.confidence, bootstrapIterations, 1)
+		method = fmt.Sprintf("bootstrap of p%g", cmd.percentile)
+		result = fmt.Sprintf("p%g difference: [%s, %s]", cmd.percentile, formatSigned(low), formatSigned(high))
+		if low > 0 || high < 0 {
+			v = verdict(float64(low))
+		}
+		return method, result, v
+	}
+
+	z, p := stats.MannWhitney(a, b)
+	result = fmt.Sprintf("p-value: %.4f (z = %+.2f)", p, z)
+	if p < 1-cmd.confidence {
+		v = verdict(z)
+	}
+	return "Mann–Whitney U", result, v
+}
+
+// verdict returns the verdict of a significant difference of the given
+// sign, positive if the responses are slower.
+func verdict(sign float64) render.Verdict {
+	if sign > 0 {
+		return render.Regression
+	}
+	return render.Improvement
+}
+
+// formatSigned formats d with its sign, rounded to the microsecond.
+func formatSigned(d time.Duration) string {
+	d = d.Round(time.Microsecond)
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
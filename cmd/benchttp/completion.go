@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benchttp/cli/internal/completion"
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/sweep"
+)
+
+// completionShells lists the shells supported by command completion.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// httpMethods lists the methods completed for the method flags.
+var httpMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"}
+
+// configFormats lists the config file formats, as accepted by
+// flags -format and -to.
+var configFormats = func() []string {
+	formats := make([]string, len(configfile.Extensions))
+	for i, ext := range configfile.Extensions {
+		formats[i] = strings.TrimPrefix(ext, ".")
+	}
+	return formats
+}()
+
+// flagCompletions maps the names of the flags whose values can be
+// completed to their completion. Other flags take free values.
+var flagCompletions = map[string]completion.Values{
+	"method":     {Choices: httpMethods},
+	"X":          {Choices: httpMethods},
+	"request":    {Choices: httpMethods},
+	"body":       {Choices: []string{"raw:"}},
+	"redact":     {Choices: []string{"header:", "query:", "body:"}},
+	"format":     {Choices: configFormats},
+	"to":         {Choices: configFormats},
+	"configFile": {Files: true, Extensions: configfile.Extensions},
+	"envFile":    {Files: true},
+	"out":        {Files: true},
+	"param":      {Choices: sweep.ParamNames()},
+	"test":       {Choices: compareTests},
+	"report":     {Files: true, Extensions: []string{".json"}},
+	"baseline":   {Files: true, Extensions: []string{".json"}},
+}
+
+// cmdCompletion handles subcommand "benchttp completion <shell>".
+type cmdCompletion struct {
+	flagset *flag.FlagSet
+}
+
+// bindFlags is a no-op: command completion has no flags.
+func (cmd *cmdCompletion) bindFlags() {}
+
+// execute writes the completion script of the given shell to stdout.
+func (cmd *cmdCompletion) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	if cmd.flagset.NArg() != 1 {
+		return fmt.Errorf("%w: expected one shell: %s", errUsage, strings.Join(completionShells, ", "))
+	}
+
+	program := completionProgram()
+	var script []byte
+	switch shell := cmd.flagset.Arg(0); shell {
+	case "bash":
+		script = completion.Bash(program)
+	case "zsh":
+		script = completion.Zsh(program)
+	case "fish":
+		script = completion.Fish(program)
+	default:
+		return fmt.Errorf("%w: unsupported shell: %s", errUsage, shell)
+	}
+
+	_, err := os.Stdout.Write(script)
+	return err
+}
+
+// completionProgram returns the description of benchttp used to generate
+// the completion scripts, from the command registry and the flags bound
+// by the commands.
+func completionProgram() completion.Program {
+	program := completion.Program{Name: "benchttp"}
+	for _, spec := range commands {
+		cmd := completion.Command{
+			Name:        spec.name,
+			Aliases:     spec.aliases,
+			Description: spec.short,
+			Arg:         spec.arg,
+			Rest:        spec.rest,
+		}
+		if spec.name == "help" {
+			cmd.Arg = completion.Values{Choices: commandNames()}
+		}
+
+		_, flagset := spec.command()
+		flagset.VisitAll(func(f *flag.Flag) {
+			cmd.Flags = append(cmd.Flags, completion.Flag{
+				Name:  f.Name,
+				Usage: f.Usage,
+				Bool:  isBoolFlag(f),
+				Value: flagCompletions[f.Name],
+			})
+		})
+
+		program.Commands = append(program.Commands, cmd)
+	}
+	return program
+}
+
+// commandNames returns the names of the registered commands.
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, spec := range commands {
+		names[i] = spec.name
+	}
+	return names
+}
+
+// isBoolFlag returns true if f takes no value.
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
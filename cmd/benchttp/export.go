@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/export"
+	"github.com/benchttp/cli/internal/redact"
+)
+
+// cmdExport handles subcommand "benchttp export <format> [options]".
+type cmdExport struct {
+	flagset *flag.FlagSet
+
+	// out is the parsed value for flag -out
+	out string
+
+	// run resolves the config from the flags of command run
+	run cmdRun
+}
+
+// exportFormats lists the supported export formats.
+var exportFormats = []string{"k6", "vegeta"}
+
+// runOnlyFlags lists the flags of command run that have no meaning
+// for an export, as nothing is run, nor for a sweep, whose reports
+// are not saved.
+var runOnlyFlags = []string{"repeat", "report", "baseline", "updateBaseline", "history"}
+
+// bindFlags binds the flags of command run and flag -out to the flagset.
+func (cmd *cmdExport) bindFlags() {
+	cmd.flagset.StringVar(&cmd.out,
+		"out",
+		"",
+		"Output file (default: stdout)",
+	)
+
+	cmd.run.flagset = cmd.flagset
+	cmd.run.bindFlags()
+}
+
+// execute converts the config resolved as for command run into the input
+// of the tool of the given format, written to the output file or stdout.
+func (cmd *cmdExport) execute(args []string) error {
+	format, args, err := shiftArgs(args)
+	if err != nil {
+		return fmt.Errorf("%w: no export format specified", errUsage)
+	}
+	if !contains(exportFormats, format) {
+		return fmt.Errorf("%w: unknown export format: %s", errUsage, format)
+	}
+
+	// resolve the config exactly as command run does, from the same
+	// config files and flags
+	cfg, err := cmd.run.makeConfig(args)
+	if err != nil {
+		return err
+	}
+	for _, name := range runOnlyFlags {
+		if isFlagSet(cmd.flagset, name) {
+			return fmt.Errorf("%w: flag -%s is not supported by command export", errUsage, name)
+		}
+	}
+
+	b, flags, warnings := cmd.export(format, cfg)
+
+	// the secrets cannot be left out without breaking the requests:
+	// detect them by exporting a redacted copy of the config
+	redactor := redact.New(cmd.run.redactRules)
+	redactor.AddValues(cmd.run.secrets...)
+	if redacted, _, _ := cmd.export(format, redactor.Config(cfg)); !bytes.Equal(b, redacted) {
+		warnings = append(warnings, fmt.Sprintf(
+			"the %s output contains sensitive values in plain text, such as credentials or values of the env file: do not share it",
+			format,
+		))
+	}
+
+	printWarnings(warnings)
+	if format == "vegeta" {
+		if cmd.out == "" {
+			defer fmt.Fprintln(os.Stderr, "pipe the output to: vegeta attack", strings.Join(flags, " "))
+		} else {
+			defer fmt.Fprintln(os.Stderr, "vegeta attack", strings.Join(flags, " "))
+		}
+	}
+
+	if cmd.out == "" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+	if err := os.WriteFile(cmd.out, b, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "wrote", cmd.out)
+	return nil
+}
+
+// export returns the input of the tool of the given format equivalent
+// to cfg, the flags of the tool if any, and warnings about the options
+// that cannot be converted.
+func (cmd *cmdExport) export(format string, cfg runner.Config) (b []byte, flags, warnings []string) {
+	switch format {
+	case "k6":
+		b, warnings = export.K6(cfg)
+	case "vegeta":
+		b, flags, warnings = export.Vegeta(cfg, cmd.out)
+	}
+	return b, flags, warnings
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benchttp/cli/internal/configfile"
+)
+
+// cmdFmt handles subcommand "benchttp fmt [options] [files...]".
+type cmdFmt struct {
+	flagset *flag.FlagSet
+
+	// check is the parsed value for flag -check
+	check bool
+
+	// to is the parsed value for flag -to
+	to string
+
+	// force is the parsed value for flag -force
+	force bool
+}
+
+// errNotFormatted signals config files that are not in canonical form.
+var errNotFormatted = fmt.Errorf("config files not formatted (run benchttp fmt)")
+
+// bindFlags binds the flags of the command to the flagset.
+func (cmd *cmdFmt) bindFlags() {
+	cmd.flagset.BoolVar(&cmd.check,
+		"check",
+		false,
+		"Do not write files, list the ones that are not formatted and fail if any",
+	)
+	cmd.flagset.StringVar(&cmd.to,
+		"to",
+		"",
+		"Convert the files to format yml, yaml or json, next to the original ones",
+	)
+	cmd.flagset.BoolVar(&cmd.force,
+		"force",
+		false,
+		"Overwrite existing files when converting",
+	)
+}
+
+// execute rewrites the given config files, or the default one,
+// in canonical form.
+func (cmd *cmdFmt) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	if cmd.check && cmd.to != "" {
+		return fmt.Errorf("%w: -check and -to are mutually exclusive", errUsage)
+	}
+
+	files := cmd.flagset.Args()
+	if len(files) == 0 {
+		defaultFile := configfile.Find([]string{
+			"./.benchttp.yml",
+			"./.benchttp.yaml",
+			"./.benchttp.json",
+		})
+		if defaultFile == "" {
+			return fmt.Errorf("%w: no config file specified", errUsage)
+		}
+		files = []string{defaultFile}
+	}
+
+	unformatted := 0
+	for _, filename := range files {
+		formatted, err := cmd.formatFile(filename)
+		if err != nil {
+			return err
+		}
+		if !formatted {
+			unformatted++
+		}
+	}
+
+	if cmd.check && unformatted != 0 {
+		return errNotFormatted
+	}
+	return nil
+}
+
+// formatFile formats a single file according to the options of cmd.
+// It returns false if the file was not formatted already.
+func (cmd *cmdFmt) formatFile(filename string) (bool, error) {
+	in, err := os.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+
+	from := filepath.Ext(filename)
+	to := from
+	if cmd.to != "" {
+		to = "." + strings.TrimPrefix(cmd.to, ".")
+	}
+
+	out, err := configfile.Format(in, from, to)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if to != from {
+		target := strings.TrimSuffix(filename, from) + to
+		if _, err := os.Stat(target); err == nil && !cmd.force {
+			return false, fmt.Errorf("%s: %w", target, errFileExists)
+		}
+		if err := os.WriteFile(target, out, 0o600); err != nil {
+			return false, err
+		}
+		fmt.Println("wrote", target)
+		return true, nil
+	}
+
+	if bytes.Equal(in, out) {
+		return true, nil
+	}
+
+	fmt.Println(filename)
+	if cmd.check {
+		return false, nil
+	}
+	return false, os.WriteFile(filename, out, 0o600)
+}
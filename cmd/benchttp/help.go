@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+// cmdHelp handles subcommand "benchttp help [command]".
+type cmdHelp struct {
+	flagset *flag.FlagSet
+}
+
+// bindFlags is a no-op: command help has no flags.
+func (cmd *cmdHelp) bindFlags() {}
+
+// execute prints the list of the commands, or the detailed help
+// of the given command.
+func (cmd *cmdHelp) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	switch cmd.flagset.NArg() {
+	case 0:
+		printCommands(os.Stdout)
+		return nil
+	case 1:
+		spec, err := lookupCommand(cmd.flagset.Arg(0))
+		if err != nil {
+			return err
+		}
+		_, flagset := spec.command()
+		spec.printHelp(os.Stdout, flagset)
+		return nil
+	default:
+		return fmt.Errorf("%w: too many arguments", errUsage)
+	}
+}
+
+// printCommands writes the general help of benchttp to w, listing
+// the registered commands.
+func printCommands(w io.Writer) {
+	fmt.Fprint(w, `benchttp runs benchmarks on HTTP endpoints and tests their results.
+
+Usage: benchttp <command> [arguments]
+
+Commands:
+`)
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, spec := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", spec.name, spec.short)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nRun 'benchttp help <command>' for details on a command.")
+}
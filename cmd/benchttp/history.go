@@ -0,0 +1,182 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benchttp/cli/internal/history"
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// historyActions lists the subcommands of command history.
+var historyActions = []string{"list", "trend", "prune", "show"}
+
+// cmdHistory handles subcommand "benchttp history <action> [options]".
+type cmdHistory struct {
+	flagset *flag.FlagSet
+
+	// config is the parsed value for flag -config
+	config string
+
+	// keep is the parsed value for flag -keep
+	keep int
+
+	// olderThan is the parsed value for flag -olderThan
+	olderThan time.Duration
+}
+
+// bindFlags binds the flags of the command to the flagset.
+func (cmd *cmdHistory) bindFlags() {
+	cmd.flagset.StringVar(&cmd.config,
+		"config",
+		"",
+		"Hash of the config of the entries to list or trend (default for trend: the latest one)",
+	)
+	cmd.flagset.IntVar(&cmd.keep,
+		"keep",
+		0,
+		"With prune, number of most recent entries to keep per config",
+	)
+	cmd.flagset.DurationVar(&cmd.olderThan,
+		"olderThan",
+		0,
+		"With prune, remove the entries older than this duration",
+	)
+}
+
+// execute runs the given history action.
+func (cmd *cmdHistory) execute(args []string) error {
+	action, args, err := shiftArgs(args)
+	if err != nil {
+		return fmt.Errorf("%w: no action specified: %s", errUsage, strings.Join(historyActions, ", "))
+	}
+
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	switch action {
+	case "list":
+		return cmd.list()
+	case "trend":
+		return cmd.trend()
+	case "prune":
+		return cmd.prune()
+	case "show":
+		return cmd.show()
+	default:
+		return fmt.Errorf("%w: unknown action: %s", errUsage, action)
+	}
+}
+
+// list renders a table of the entries of the history.
+func (cmd *cmdHistory) list() error {
+	entries, err := cmd.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No history entries: run benchttp run -history to record one.")
+		return nil
+	}
+
+	_, err = render.HistoryList(os.Stdout, entries)
+	return err
+}
+
+// trend renders a table of the summary metrics of the entries of a same
+// config over time.
+func (cmd *cmdHistory) trend() error {
+	if cmd.config == "" {
+		latest, err := history.Find(history.DefaultDir, "latest")
+		if err != nil {
+			return err
+		}
+		cmd.config = latest.ConfigHash
+	}
+
+	entries, err := cmd.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: %s", history.ErrNotFound, cmd.config)
+	}
+
+	_, err = render.Trend(os.Stdout, entries)
+	return err
+}
+
+// prune removes the entries of the history beyond -keep or older
+// than -olderThan.
+func (cmd *cmdHistory) prune() error {
+	if cmd.keep <= 0 && cmd.olderThan <= 0 {
+		return fmt.Errorf("%w: prune requires -keep or -olderThan", errUsage)
+	}
+
+	var before time.Time
+	if cmd.olderThan > 0 {
+		before = time.Now().Add(-cmd.olderThan)
+	}
+
+	removed, err := history.Prune(history.DefaultDir, cmd.keep, before)
+	for _, entry := range removed {
+		fmt.Println("removed", entry.ID)
+	}
+	return err
+}
+
+// show renders the report of the given entry, as command run does.
+func (cmd *cmdHistory) show() error {
+	if cmd.flagset.NArg() != 1 {
+		return fmt.Errorf("%w: expected one entry id, or latest", errUsage)
+	}
+
+	entry, err := history.Find(history.DefaultDir, cmd.flagset.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	commit := entry.Commit
+	if commit == "" {
+		commit = "-"
+	}
+
+	fmt.Println(ansi.Bold(entry.ID))
+	fmt.Printf("%-18s %s\n", "Benchmark", entry.Name)
+	fmt.Printf("%-18s %s\n", "Time", entry.Time.Local().Format(time.RFC3339))
+	fmt.Printf("%-18s %s\n\n", "Commit", commit)
+
+	if _, err := render.ReportSummary(os.Stdout, entry.Report); err != nil {
+		return err
+	}
+	_, err = render.TestSuite(os.Stdout, entry.Report.Tests, entry.Warnings)
+	return err
+}
+
+// entries returns the entries of the history, only the ones of the
+// config of flag -config if set.
+func (cmd *cmdHistory) entries() ([]render.HistoryEntry, error) {
+	entries, err := history.List(history.DefaultDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := []render.HistoryEntry{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.ConfigHash, cmd.config) {
+			continue
+		}
+		rendered = append(rendered, render.HistoryEntry{
+			ID:         entry.ID,
+			Name:       entry.Name,
+			ConfigHash: entry.ConfigHash,
+			Commit:     entry.ShortCommit(),
+			Time:       entry.Time,
+			Report:     entry.Report,
+		})
+	}
+	return rendered, nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/importer"
+)
+
+// cmdImport handles subcommand "benchttp import <format> [options] <input>".
+type cmdImport struct {
+	flagset *flag.FlagSet
+
+	// out is the parsed value for flag -out
+	out string
+
+	// force is the parsed value for flag -force
+	force bool
+
+	// har are the parsed values for the flags of format har
+	har importer.HAROptions
+
+	// openapi are the parsed values for the flags of format openapi
+	openapi importer.OpenAPIOptions
+}
+
+// importFormats maps the supported import formats to their specific flags.
+var importFormats = map[string][]string{
+	"curl":    {},
+	"har":     {"host", "path", "stripHeader", "extends"},
+	"openapi": {"server", "operation", "tag"},
+	"postman": {},
+}
+
+// importFormatNames returns the names of the supported import formats,
+// sorted.
+func importFormatNames() []string {
+	names := make([]string, 0, len(importFormats))
+	for name := range importFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bindFlags binds the flags of all import formats to the flagset.
+func (cmd *cmdImport) bindFlags() {
+	cmd.flagset.StringVar(&cmd.out,
+		"out",
+		"",
+		"Output file for curl (default .benchttp.yml), output directory for other formats (default .)",
+	)
+	cmd.flagset.BoolVar(&cmd.force,
+		"force",
+		false,
+		"Overwrite existing files",
+	)
+
+	// har
+	cmd.flagset.StringVar(&cmd.har.Host,
+		"host",
+		"",
+		"har: glob pattern of the hosts to import",
+	)
+	cmd.flagset.StringVar(&cmd.har.Path,
+		"path",
+		"",
+		"har: glob pattern of the URL paths to import",
+	)
+	cmd.flagset.Var((*stringsValue)(&cmd.har.StripHeaders),
+		"stripHeader",
+		"har: glob pattern of extra headers to strip (repeatable)",
+	)
+	cmd.flagset.BoolVar(&cmd.har.Extends,
+		"extends",
+		false,
+		"har: move shared headers to a base file extended by the others",
+	)
+
+	// openapi
+	cmd.flagset.StringVar(&cmd.openapi.Server,
+		"server",
+		"",
+		"openapi: base URL of the requests (default: first server of the spec)",
+	)
+	cmd.flagset.Var((*stringsValue)(&cmd.openapi.Operations),
+		"operation",
+		"openapi: id of an operation to import (repeatable)",
+	)
+	cmd.flagset.Var((*stringsValue)(&cmd.openapi.Tags),
+		"tag",
+		"openapi: tag of the operations to import (repeatable)",
+	)
+}
+
+// execute converts the input of the given format into config files.
+func (cmd *cmdImport) execute(args []string) error {
+	format, args, err := shiftArgs(args)
+	if err != nil {
+		return fmt.Errorf("%w: no import format specified", errUsage)
+	}
+	if _, ok := importFormats[format]; !ok {
+		return fmt.Errorf("%w: unknown import format: %s", errUsage, format)
+	}
+
+	args, err = cmd.parseArgs(format, args)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "curl":
+		return cmd.importCurl(args)
+	case "har":
+		return cmd.importHAR(args)
+	case "openapi":
+		return cmd.importOpenAPI(args)
+	default:
+		return cmd.importPostman(args)
+	}
+}
+
+// parseArgs parses the flags, failing if a flag specific to another
+// format is set, and returns the remaining args. The args of a curl
+// command are not parsed: the flags end at the first arg that is not
+// a flag of the command, such as "curl" or "-H".
+func (cmd *cmdImport) parseArgs(format string, args []string) ([]string, error) {
+	if format == "curl" {
+		n := leadingFlags(cmd.flagset, args)
+		cmd.flagset.Parse(args[:n]) //nolint:errcheck // never occurs due to flag.ExitOnError
+		args = args[n:]
+	} else {
+		cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+		args = cmd.flagset.Args()
+	}
+
+	var err error
+	cmd.flagset.Visit(func(f *flag.Flag) {
+		for other, flags := range importFormats {
+			if other != format && contains(flags, f.Name) && err == nil {
+				err = fmt.Errorf("%w: flag -%s is not supported by format %s", errUsage, f.Name, format)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.out == "" {
+		cmd.out = "."
+		if format == "curl" {
+			cmd.out = ".benchttp.yml"
+		}
+	}
+
+	return args, nil
+}
+
+// leadingFlags returns the number of args at the start of args that are
+// flags of flagset and their values, up to a terminating "--" included.
+func leadingFlags(flagset *flag.FlagSet, args []string) int {
+	n := 0
+	for n < len(args) {
+		arg := args[n]
+		if arg == "--" {
+			return n + 1
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return n
+		}
+		name := strings.TrimLeft(arg, "-")
+		hasValue := strings.Contains(name, "=")
+		name = strings.SplitN(name, "=", 2)[0]
+
+		f := flagset.Lookup(name)
+		if f == nil {
+			return n
+		}
+		n++
+		if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); !hasValue && !(ok && boolFlag.IsBoolFlag()) {
+			n++ // value of the flag
+		}
+	}
+	if n > len(args) {
+		return len(args)
+	}
+	return n
+}
+
+// importCurl handles "benchttp import curl [options] <curl command>".
+// The curl command can be passed as a single string, as separate args,
+// or via stdin if omitted.
+func (cmd *cmdImport) importCurl(args []string) error {
+	var (
+		repr     configfile.Representation
+		warnings []string
+		err      error
+	)
+
+	switch len(args) {
+	case 0:
+		b, readErr := io.ReadAll(os.Stdin)
+		if readErr != nil {
+			return readErr
+		}
+		repr, warnings, err = importer.CurlCommand(string(b))
+	case 1:
+		repr, warnings, err = importer.CurlCommand(args[0])
+	default:
+		repr, warnings, err = importer.Curl(args)
+	}
+
+	printWarnings(warnings)
+	if err != nil {
+		return err
+	}
+
+	return writeConfigFile(cmd.out, repr, cmd.force)
+}
+
+// importHAR handles "benchttp import har [options] <file.har>".
+// It writes one config file per imported request in the output directory.
+func (cmd *cmdImport) importHAR(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected exactly one HAR file", errUsage)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	files, warnings, err := importer.HAR(f, cmd.har)
+	printWarnings(warnings)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s: no request matching the filters", args[0])
+	}
+
+	return writeConfigFiles(cmd.out, files, cmd.force)
+}
+
+// importOpenAPI handles "benchttp import openapi [options] <spec>".
+// It writes one config file per imported operation in the output directory.
+func (cmd *cmdImport) importOpenAPI(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected exactly one OpenAPI specification", errUsage)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	files, warnings, err := importer.OpenAPI(f, cmd.openapi)
+	printWarnings(warnings)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s: no operation matching the filters", args[0])
+	}
+
+	return writeConfigFiles(cmd.out, files, cmd.force)
+}
+
+// importPostman handles "benchttp import postman [options] <collection>".
+// It writes the requests of the collection in the output directory,
+// with a subdirectory per folder.
+func (cmd *cmdImport) importPostman(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected exactly one Postman collection", errUsage)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	files, warnings, err := importer.Postman(f, importer.PostmanOptions{})
+	printWarnings(warnings)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%s: no request in collection", args[0])
+	}
+
+	return writeConfigFiles(cmd.out, files, cmd.force)
+}
+
+// errFileExists signals a generated file that would overwrite
+// an existing one.
+var errFileExists = errors.New("file already exists (use -force to overwrite)")
+
+// writeConfigFile writes repr to filename, failing if the file
+// already exists unless force is true.
+func writeConfigFile(filename string, repr configfile.Representation, force bool) error {
+	if _, err := os.Stat(filename); err == nil && !force {
+		return fmt.Errorf("%s: %w", filename, errFileExists)
+	}
+	if err := configfile.Write(filename, repr); err != nil {
+		return err
+	}
+	fmt.Println("wrote", filename)
+	return nil
+}
+
+// writeConfigFiles writes files in dir. It fails before writing
+// anything if any of the files already exists, unless force is true.
+func writeConfigFiles(dir string, files []importer.File, force bool) error {
+	if !force {
+		for _, f := range files {
+			filename := filepath.Join(dir, f.Name)
+			if _, err := os.Stat(filename); err == nil {
+				return fmt.Errorf("%s: %w", filename, errFileExists)
+			}
+		}
+	}
+	for _, f := range files {
+		if err := writeConfigFile(filepath.Join(dir, f.Name), f.Representation, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringsValue implements flag.Value for a repeatable string flag.
+type stringsValue []string
+
+// String returns a string representation of the values.
+func (v *stringsValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.Join(*v, ",")
+}
+
+// Set appends value to the values.
+func (v *stringsValue) Set(value string) error {
+	*v = append(*v, value)
+	return nil
+}
+
+// printWarnings writes warnings to stderr.
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", strings.TrimSpace(w))
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/configflag"
+)
+
+// initComments are the comments written in the generated config file.
+var initComments = configfile.Comments{
+	"": "benchttp config file, generated by benchttp init.\n" +
+		"Documentation: https://github.com/benchttp/cli#configuration",
+	"request":               "HTTP request to benchmark",
+	"request.method":        "HTTP method",
+	"request.url":           "target url, can reference environment variables",
+	"runner":                "load parameters",
+	"runner.requests":       "total number of requests, -1 for unlimited",
+	"runner.concurrency":    "number of concurrent workers",
+	"runner.interval":       "minimum delay between two requests of a worker",
+	"runner.requestTimeout": "timeout of a single request",
+	"runner.globalTimeout":  "maximum duration of the benchmark",
+	"tests":                 "tests run against the results of the benchmark",
+}
+
+// initPrompts lists the config fields prompted by "benchttp init",
+// in order.
+var initPrompts = []string{
+	runner.ConfigFieldURL,
+	runner.ConfigFieldMethod,
+	runner.ConfigFieldRequests,
+	runner.ConfigFieldConcurrency,
+	runner.ConfigFieldInterval,
+	runner.ConfigFieldRequestTimeout,
+	runner.ConfigFieldGlobalTimeout,
+}
+
+// cmdInit handles subcommand "benchttp init [options]".
+type cmdInit struct {
+	flagset *flag.FlagSet
+
+	// out is the parsed value for flag -out
+	out string
+
+	// format is the parsed value for flag -format
+	format string
+
+	// force is the parsed value for flag -force
+	force bool
+
+	// yes is the parsed value for flag -yes
+	yes bool
+
+	// tests is the parsed value for flag -tests
+	tests bool
+
+	// maxResponseTime is the parsed value for flag -maxResponseTime
+	maxResponseTime time.Duration
+
+	// config is the config built from the flags and the answers
+	config runner.Config
+
+	// in and out are the input and output of the prompts
+	in     *bufio.Reader
+	stdout io.Writer
+}
+
+// execute writes a commented config file from the flags, prompting
+// for the values that are not set unless -yes is set.
+func (cmd *cmdInit) execute(args []string) error {
+	cmd.in = bufio.NewReader(os.Stdin)
+	cmd.stdout = os.Stdout
+
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+	fields := configflag.Which(cmd.flagset)
+
+	filename := cmd.out
+	if filename == "" {
+		filename = ".benchttp." + cmd.format
+	}
+	if _, err := os.Stat(filename); err == nil && !cmd.force {
+		return fmt.Errorf("%s: %w", filename, errFileExists)
+	}
+
+	if !cmd.yes {
+		if err := cmd.prompt(fields); err != nil {
+			return err
+		}
+	}
+
+	if cmd.config.Request.URL.String() == "" {
+		return fmt.Errorf("%w: no url specified", errUsage)
+	}
+
+	repr := cmd.representation()
+	if err := configfile.WriteComments(filename, repr, initComments); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.stdout, "wrote", filename)
+	return nil
+}
+
+// bindFlags binds the config flags and the init flags to the flagset.
+func (cmd *cmdInit) bindFlags() {
+	cmd.config = runner.DefaultConfig()
+
+	cmd.flagset.StringVar(&cmd.out,
+		"out",
+		"",
+		"Output file (default: .benchttp.<format>)",
+	)
+	cmd.flagset.StringVar(&cmd.format,
+		"format",
+		"yml",
+		"Format of the config file: yml, yaml or json",
+	)
+	cmd.flagset.BoolVar(&cmd.force,
+		"force",
+		false,
+		"Overwrite an existing file",
+	)
+	cmd.flagset.BoolVar(&cmd.yes,
+		"yes",
+		false,
+		"Do not prompt, use the flags and the defaults",
+	)
+	cmd.flagset.BoolVar(&cmd.tests,
+		"tests",
+		true,
+		"Add starter tests",
+	)
+	cmd.flagset.DurationVar(&cmd.maxResponseTime,
+		"maxResponseTime",
+		time.Second,
+		"Maximum mean response time of the starter tests",
+	)
+
+	configflag.Bind(cmd.flagset, &cmd.config)
+}
+
+// errNoAnswer signals a prompt that could not be answered.
+var errNoAnswer = errors.New("no answer")
+
+// prompt asks for the values of the fields that are not set yet,
+// and for the starter tests.
+func (cmd *cmdInit) prompt(fields []string) error {
+	for _, field := range initPrompts {
+		if contains(fields, field) {
+			continue
+		}
+		def := cmd.flagset.Lookup(field).Value.String()
+		if err := cmd.ask(field, def, func(answer string) error {
+			if err := cmd.flagset.Set(field, answer); err != nil {
+				return fmt.Errorf("invalid value %q for %s", answer, field)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !isFlagSet(cmd.flagset, "tests") {
+		if err := cmd.ask("add starter tests (y/n)", "y", func(answer string) error {
+			switch strings.ToLower(answer) {
+			case "y", "yes":
+				cmd.tests = true
+			case "n", "no":
+				cmd.tests = false
+			default:
+				return fmt.Errorf("invalid answer: %s", answer)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cmd.tests && !isFlagSet(cmd.flagset, "maxResponseTime") {
+		if err := cmd.ask("maximum mean response time", cmd.maxResponseTime.String(), func(answer string) error {
+			if err := cmd.flagset.Set("maxResponseTime", answer); err != nil {
+				return fmt.Errorf("invalid duration: %s", answer)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ask prints a question with its default value and calls set with
+// the answer, until set succeeds. An empty answer keeps the default
+// value.
+func (cmd *cmdInit) ask(question, def string, set func(answer string) error) error {
+	for {
+		if def != "" {
+			fmt.Fprintf(cmd.stdout, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(cmd.stdout, "%s: ", question)
+		}
+
+		line, err := cmd.in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			fmt.Fprintln(cmd.stdout)
+			if def != "" {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", question, errNoAnswer)
+		}
+
+		if answer == "" {
+			if def != "" {
+				return nil
+			}
+			continue
+		}
+
+		if setErr := set(answer); setErr != nil {
+			fmt.Fprintln(cmd.stdout, setErr)
+			if err != nil {
+				return setErr
+			}
+			continue
+		}
+		return nil
+	}
+}
+
+// representation returns the representation of the config file
+// to write.
+func (cmd *cmdInit) representation() configfile.Representation {
+	cfg := cmd.config
+
+	var repr configfile.Representation
+	method, rawURL := cfg.Request.Method, cfg.Request.URL.String()
+	repr.Request.Method = &method
+	repr.Request.URL = &rawURL
+	if len(cfg.Request.Header) != 0 {
+		repr.Request.Header = cfg.Request.Header
+	}
+	if body := cfg.Request.Body; len(body.Content) != 0 {
+		repr.Request.Body = &configfile.BodyRepresentation{Type: body.Type, Content: string(body.Content)}
+	}
+
+	interval := configfile.FormatDuration(cfg.Runner.Interval)
+	requestTimeout := configfile.FormatDuration(cfg.Runner.RequestTimeout)
+	globalTimeout := configfile.FormatDuration(cfg.Runner.GlobalTimeout)
+	repr.Runner.Requests = &cfg.Runner.Requests
+	repr.Runner.Concurrency = &cfg.Runner.Concurrency
+	repr.Runner.Interval = &interval
+	repr.Runner.RequestTimeout = &requestTimeout
+	repr.Runner.GlobalTimeout = &globalTimeout
+
+	if cmd.tests {
+		repr.Tests = starterTests(cmd.maxResponseTime)
+	}
+
+	return repr
+}
+
+// starterTests returns the tests written by default in a new config file.
+func starterTests(maxResponseTime time.Duration) []configfile.TestRepresentation {
+	test := func(name, field, predicate string, target interface{}) configfile.TestRepresentation {
+		return configfile.TestRepresentation{Name: &name, Field: &field, Predicate: &predicate, Target: target}
+	}
+	return []configfile.TestRepresentation{
+		test("mean response time", "ResponseTimes.Mean", "LTE", configfile.FormatDuration(maxResponseTime)),
+		test("no request failure", "RequestFailureCount", "EQ", 0),
+	}
+}
+
+// isFlagSet returns true if the flag name was set on the command line.
+func isFlagSet(flagset *flag.FlagSet, name string) bool {
+	set := false
+	flagset.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
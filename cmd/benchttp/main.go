@@ -2,7 +2,6 @@ package main
 
 import (
 	"errors"
-	"flag"
 	"fmt"
 	"os"
 )
@@ -14,7 +13,7 @@ func main() {
 	if err := run(); err != nil {
 		fmt.Println(err)
 		if errors.Is(err, errUsage) {
-			flag.Usage()
+			fmt.Fprintln(os.Stderr, usageHint(os.Args[1:]))
 		}
 		os.Exit(1)
 	}
@@ -26,11 +25,12 @@ func run() error {
 		return err
 	}
 
-	cmd, err := commandOf(commandName)
+	spec, err := lookupCommand(commandName)
 	if err != nil {
 		return err
 	}
 
+	cmd, _ := spec.command()
 	return cmd.execute(options)
 }
 
@@ -43,16 +43,20 @@ func shiftArgs(args []string) (commandName string, nextArgs []string, err error)
 
 // command is the interface that all benchttp subcommands must implement.
 type command interface {
+	// bindFlags binds the flags of the command to its flagset.
+	bindFlags()
+
+	// execute parses args and runs the command.
 	execute(args []string) error
 }
 
-func commandOf(name string) (command, error) {
-	switch name {
-	case "run":
-		return &cmdRun{flagset: flag.NewFlagSet("run", flag.ExitOnError)}, nil
-	case "version":
-		return &cmdVersion{}, nil
-	default:
-		return nil, fmt.Errorf("%w: unknown command: %s", errUsage, name)
+// usageHint returns the message printed after a usage error, pointing
+// to the help of the command in args if any.
+func usageHint(args []string) string {
+	if len(args) != 0 {
+		if spec, err := lookupCommand(args[0]); err == nil && spec.name != "help" {
+			return fmt.Sprintf("Run 'benchttp help %s' for usage.", spec.name)
+		}
 	}
+	return "Run 'benchttp help' for usage."
 }
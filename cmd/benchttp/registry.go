@@ -0,0 +1,402 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/benchttp/cli/internal/completion"
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/history"
+)
+
+// commandSpec describes a benchttp subcommand: its names, its help
+// and how to build it.
+type commandSpec struct {
+	// name is the name of the command, as typed after benchttp.
+	name string
+
+	// aliases are alternative names of the command.
+	aliases []string
+
+	// usage lists the arguments of the command, following its name.
+	usage string
+
+	// short is a one-line description of the command.
+	short string
+
+	// long is a detailed description of the command.
+	long string
+
+	// examples are command lines illustrating the usage of the command.
+	examples []string
+
+	// arg and rest are the shell completions of the first positional
+	// argument and of the following ones.
+	arg, rest completion.Values
+
+	// newCommand returns the command, using flagset for its flags.
+	newCommand func(flagset *flag.FlagSet) command
+}
+
+// commands is the registry of the benchttp subcommands, in the order
+// they are listed by benchttp help.
+var commands = []commandSpec{
+	{
+		name:    "run",
+		aliases: []string{"r"},
+		usage:   "[options] [url | config files...]",
+		short:   "Run a benchmark",
+		long: `Run sends the configured requests to the target url, renders a summary
+of the results and runs the tests of the config file against them.
+
+The config is resolved from, by increasing priority: the defaults,
+the config file (.benchttp.yml, .benchttp.yaml or .benchttp.json in the
+current directory, unless -configFile is set), and the flags. Like curl,
+the url can be passed as a positional argument.
+
+With -configDir or config files (or glob patterns) as arguments, the
+benchmark of each config file is run in turn, and a table comparing them
+is rendered at the end. If the config file declares scenarios, each
+of them is run the same way, unless -scenario selects one.
+
+With -repeat, each benchmark is run several times and the spread of the
+summary metrics is reported; the tests are run against their means.
+
+With -baseline, the report is compared with a baseline report written
+by -report or -updateBaseline, and the run fails if a metric regresses
+beyond its tolerance (see baseline.tolerances in config files).
+-updateBaseline rewrites the baseline with the new report instead.
+
+Exits with a non-zero status if a test fails or a metric regresses.`,
+		examples: []string{
+			"benchttp run",
+			"benchttp run -configFile bench/users.yml -requests 500",
+			"benchttp run https://example.com -concurrency 10 -globalTimeout 30s",
+			"benchttp run -configDir ./bench/ -failFast",
+			"benchttp run 'bench/users-*.yml'",
+			"benchttp run -configFile journey.yml -scenario checkout",
+			"benchttp run -repeat 5",
+			"benchttp run -report before.json",
+			"benchttp run -history",
+			"benchttp run -baseline baseline.json",
+			"benchttp run -baseline baseline.json -updateBaseline",
+			"benchttp run -X POST -H 'Content-Type: application/json' -d '{\"a\":1}' https://example.com",
+		},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdRun{flagset: flagset}
+		},
+	},
+	{
+		name:  "init",
+		usage: "[options]",
+		short: "Create a commented config file",
+		long: `Init writes a commented config file built from the flags, prompting for
+the main values that are not set, unless -yes is set.`,
+		examples: []string{
+			"benchttp init",
+			"benchttp init -yes -url https://example.com -format json",
+		},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdInit{flagset: flagset}
+		},
+	},
+	{
+		name:  "import",
+		usage: "<curl|har|openapi|postman> [options] <input>",
+		short: "Convert a curl command, HAR file, OpenAPI spec or Postman collection",
+		long: `Import converts an existing description of requests into config files.
+
+A curl command is converted into a single config file. HAR files, OpenAPI
+specifications and Postman collections are converted into one config file
+per request, in the output directory.
+
+The flags prefixed with a format name are specific to that format.`,
+		examples: []string{
+			`benchttp import curl "curl 'https://example.com' -H 'accept: */*'"`,
+			"benchttp import curl -out users.yml curl https://example.com -H 'accept: */*'",
+			"benchttp import har -host 'api.*' -stripHeader 'X-Trace-*' session.har",
+			"benchttp import openapi -out bench -tag users spec.yaml",
+			"benchttp import postman -out bench collection.json",
+		},
+		arg:  completion.Values{Choices: importFormatNames()},
+		rest: completion.Values{Files: true, Extensions: []string{".har", ".json", ".yaml", ".yml"}},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdImport{flagset: flagset}
+		},
+	},
+	{
+		name:  "compare",
+		usage: "[options] <before.json> <after.json>",
+		short: "Test whether two benchmarks differ significantly",
+		long: `Compare reads two reports written by benchttp run -report and tests
+whether their response times differ significantly at the confidence level
+of -confidence, using all the requests of the benchmarks rather than
+their means.
+
+The mannwhitney test is a Mann–Whitney U test on the distributions of the
+response times. The bootstrap test estimates the confidence interval of
+the difference of a percentile by resampling.
+
+Exits with a non-zero status if the second benchmark is significantly
+slower, for use in CI.`,
+		examples: []string{
+			"benchttp compare before.json after.json",
+			"benchttp compare -confidence 0.99 before.json after.json",
+			"benchttp compare -test bootstrap -percentile 95 before.json after.json",
+		},
+		rest: completion.Values{Files: true, Extensions: []string{".json"}},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdCompare{flagset: flagset}
+		},
+	},
+	{
+		name:  "history",
+		usage: "<list|trend|prune|show> [options]",
+		short: "Browse the reports saved by benchttp run -history",
+		long: `History browses the local history of the reports saved by benchttp run
+-history in ` + history.DefaultDir + `, each entry being identified by the time
+of the run, the hash of its config (request and runner options) and the
+git commit it was run at.
+
+  list   lists the entries, only the ones of -config if set
+  trend  renders the metrics of the entries of a config over time
+  prune  removes the entries beyond -keep per config or older than -olderThan
+  show   renders the report of an entry, given its id (or a prefix) or latest`,
+		examples: []string{
+			"benchttp run -history",
+			"benchttp history list",
+			"benchttp history trend -config 3f2a9c",
+			"benchttp history prune -keep 20 -olderThan 720h",
+			"benchttp history show latest",
+		},
+		arg: completion.Values{Choices: historyActions},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdHistory{flagset: flagset}
+		},
+	},
+	{
+		name:  "sweep",
+		usage: "[options] [url | config file]",
+		short: "Run a benchmark for each value of a runner parameter",
+		long: `Sweep resolves the config as command run does, then runs its benchmark
+once per value of the parameter set by -param, from the list or range
+of -values. It renders a table and charts of the throughput and latency
+percentiles versus the parameter, highlighting the knee: the last value
+before the latency climbs faster than the throughput.
+
+The tests of the config file are not run.`,
+		examples: []string{
+			"benchttp sweep -values 1..64*2",
+			"benchttp sweep -configFile bench/users.yml -param concurrency -values 1,5,10,50",
+			"benchttp sweep -param interval -values 0s..50ms+10ms https://example.com",
+		},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdSweep{flagset: flagset}
+		},
+	},
+	{
+		name:  "export",
+		usage: "<k6|vegeta> [options]",
+		short: "Generate a k6 script or vegeta targets from a config",
+		long: `Export resolves the config as command run does, from the same config
+files and flags, and converts it into the input of another load testing
+tool. For vegeta, the flags of the attack are printed on stderr.`,
+		examples: []string{
+			"benchttp export k6 -out script.js",
+			"benchttp export vegeta -configFile bench/users.yml -out targets.json",
+		},
+		arg: completion.Values{Choices: exportFormats},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdExport{flagset: flagset}
+		},
+	},
+	{
+		name:    "fmt",
+		aliases: []string{"format"},
+		usage:   "[options] [files...]",
+		short:   "Rewrite config files in canonical form",
+		long: `Fmt rewrites the given config files, or the default one, in canonical
+form: keys in documented order, normalized durations and 2-space
+indentation. Comments of YAML files are preserved.`,
+		examples: []string{
+			"benchttp fmt",
+			"benchttp fmt -check bench/*.yml",
+			"benchttp fmt -to json .benchttp.yml",
+		},
+		rest: completion.Values{Files: true, Extensions: configfile.Extensions},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdFmt{flagset: flagset}
+		},
+	},
+	{
+		name:  "schema",
+		usage: "[options]",
+		short: "Print the JSON Schema of config files",
+		long: `Schema writes the JSON Schema of config files, for validation and
+completion in editors.`,
+		examples: []string{
+			"benchttp schema -out benchttp.schema.json",
+		},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdSchema{flagset: flagset}
+		},
+	},
+	{
+		name:  "completion",
+		usage: "<bash|zsh|fish>",
+		short: "Generate a shell completion script",
+		long: `Completion writes the completion script of the given shell to stdout.
+It completes the commands, their flags and the values of the flags
+that accept a fixed set of values or files.
+
+To load the completions in the current shell:
+
+  bash: source <(benchttp completion bash)
+  zsh:  source <(benchttp completion zsh)
+  fish: benchttp completion fish | source
+
+To load them in every session, install the script in the completion
+directory of the shell, e.g. ~/.config/fish/completions/benchttp.fish
+for fish or a directory of $fpath as _benchttp for zsh.`,
+		examples: []string{
+			"benchttp completion bash > /etc/bash_completion.d/benchttp",
+			"benchttp completion zsh > \"${fpath[1]}/_benchttp\"",
+			"benchttp completion fish > ~/.config/fish/completions/benchttp.fish",
+		},
+		arg: completion.Values{Choices: completionShells},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdCompletion{flagset: flagset}
+		},
+	},
+	{
+		name:  "version",
+		short: "Print the version of benchttp",
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdVersion{flagset: flagset}
+		},
+	},
+	{
+		name:    "help",
+		aliases: []string{"-h", "-help", "--help"},
+		usage:   "[command]",
+		short:   "Show the help of benchttp or of a command",
+		examples: []string{
+			"benchttp help",
+			"benchttp help run",
+		},
+		newCommand: func(flagset *flag.FlagSet) command {
+			return &cmdHelp{flagset: flagset}
+		},
+	},
+}
+
+// lookupCommand returns the spec of the command of the given name
+// or alias. If there is none, the returned error suggests the commands
+// with a similar name.
+func lookupCommand(name string) (commandSpec, error) {
+	for _, spec := range commands {
+		if spec.name == name || contains(spec.aliases, name) {
+			return spec, nil
+		}
+	}
+
+	err := fmt.Errorf("%w: unknown command: %s", errUsage, name)
+	if suggestions := suggestCommands(name); len(suggestions) != 0 {
+		err = fmt.Errorf("%w\n\nDid you mean this?\n\t%s", err, strings.Join(suggestions, "\n\t"))
+	}
+	return commandSpec{}, err
+}
+
+// command returns the command of the spec with its flags bound
+// to the returned flagset, that prints the help of the command on -h.
+func (spec commandSpec) command() (command, *flag.FlagSet) {
+	flagset := flag.NewFlagSet(spec.name, flag.ExitOnError)
+	flagset.Usage = func() {
+		spec.printHelp(flagset.Output(), flagset)
+	}
+	cmd := spec.newCommand(flagset)
+	cmd.bindFlags()
+	return cmd, flagset
+}
+
+// printHelp writes the detailed help of the command to w, including
+// the flags defined in flagset.
+func (spec commandSpec) printHelp(w io.Writer, flagset *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: benchttp %s\n", strings.TrimSpace(spec.name+" "+spec.usage))
+
+	description := spec.long
+	if description == "" {
+		description = spec.short + "."
+	}
+	fmt.Fprintf(w, "\n%s\n", description)
+
+	if len(spec.aliases) != 0 {
+		fmt.Fprintf(w, "\nAliases: %s\n", strings.Join(spec.aliases, ", "))
+	}
+
+	hasFlags := false
+	flagset.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(w, "\nOptions:")
+		out := flagset.Output()
+		flagset.SetOutput(w)
+		flagset.PrintDefaults()
+		flagset.SetOutput(out)
+	}
+
+	if len(spec.examples) != 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for _, example := range spec.examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+}
+
+// suggestCommands returns the names of the commands that name
+// is likely a typo of: the ones it is a prefix of, or the ones
+// at an edit distance of at most 2.
+func suggestCommands(name string) []string {
+	suggestions := []string{}
+	if name == "" {
+		return suggestions
+	}
+	for _, spec := range commands {
+		if strings.HasPrefix(spec.name, name) || editDistance(name, spec.name) <= 2 {
+			suggestions = append(suggestions, spec.name)
+		}
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	m := first
+	for _, v := range others {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
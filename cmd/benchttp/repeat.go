@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/output"
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+	"github.com/benchttp/cli/internal/stats"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+// repeatConfidence is the confidence level of the intervals
+// reported for repeated runs.
+const repeatConfidence = 0.95
+
+// summaryMetrics lists the metrics of the report summary, as estimated
+// over repeated runs.
+var summaryMetrics = []struct {
+	name     string
+	duration bool
+	value    func(*runner.Report) float64
+}{
+	{
+		name:  "Requests",
+		value: func(rep *runner.Report) float64 { return float64(len(rep.Metrics.Records)) },
+	},
+	{
+		name:  "Errors",
+		value: func(rep *runner.Report) float64 { return float64(len(rep.Metrics.RequestFailures)) },
+	},
+	{
+		name:     "Min response time",
+		duration: true,
+		value:    func(rep *runner.Report) float64 { return float64(rep.Metrics.ResponseTimes.Min) },
+	},
+	{
+		name:     "Max response time",
+		duration: true,
+		value:    func(rep *runner.Report) float64 { return float64(rep.Metrics.ResponseTimes.Max) },
+	},
+	{
+		name:     "Mean response time",
+		duration: true,
+		value:    func(rep *runner.Report) float64 { return float64(rep.Metrics.ResponseTimes.Mean) },
+	},
+	{
+		name:     "Total duration",
+		duration: true,
+		value:    func(rep *runner.Report) float64 { return float64(rep.Metadata.TotalDuration) },
+	},
+}
+
+// runRepeated runs the benchmark as many times as set by -repeat,
+// then renders the estimates of the summary metrics over the runs and
+// the results of the tests against the means of their metrics, relative
+// tests included.
+//
+// The returned report is the one of the last run, its summary metrics
+// and test results replaced by the aggregated ones.
+func (cmd *cmdRun) runRepeated(ctx context.Context, bench benchmark) (*runner.Report, error) {
+	stdout := output.ConditionalWriter{Writer: os.Stdout}.If(!cmd.silent)
+
+	runs := make(stats.Runs, 0, cmd.repeat)
+	for i := 0; i < cmd.repeat; i++ {
+		fmt.Fprintln(stdout, ansi.Grey(fmt.Sprintf("run %d/%d", i+1, cmd.repeat)))
+
+		report, err := runBenchmark(ctx, bench.config, cmd.silent)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, report)
+	}
+
+	estimates := make([]render.Estimate, len(summaryMetrics))
+	for i, metric := range summaryMetrics {
+		e := runs.Estimate(metric.value, repeatConfidence)
+		estimates[i] = render.Estimate{
+			Name:     metric.name,
+			Duration: metric.duration,
+			Mean:     e.Mean,
+			StdDev:   e.StdDev,
+			Low:      e.Low(),
+			High:     e.High(),
+		}
+	}
+
+	redactor := cmd.redactor()
+	report := redactor.Report(aggregateRuns(runs, bench.config.Tests))
+	cmd.runTests(bench, report, runs.Mean)
+	w := redactor.Writer(os.Stdout)
+
+	if err := cmd.saveReport(bench, report); err != nil {
+		return nil, err
+	}
+
+	if _, err := render.RepeatSummary(
+		output.ConditionalWriter{Writer: w}.If(!cmd.silent),
+		report, len(runs), repeatConfidence, estimates,
+	); err != nil {
+		return nil, err
+	}
+
+	return report, cmd.renderChecks(w, bench, report)
+}
+
+// aggregateRuns returns a copy of the report of the last run, its
+// summary metrics replaced by their means over the runs, and its test
+// results by the ones of cases run against the means of their metrics.
+func aggregateRuns(runs stats.Runs, cases []runner.TestCase) *runner.Report {
+	meanDuration := func(value func(*runner.Report) time.Duration) time.Duration {
+		sum := time.Duration(0)
+		for _, rep := range runs {
+			sum += value(rep)
+		}
+		return sum / time.Duration(len(runs))
+	}
+
+	report := *runs[len(runs)-1]
+	report.Metrics.ResponseTimes.Min = meanDuration(func(rep *runner.Report) time.Duration { return rep.Metrics.ResponseTimes.Min })
+	report.Metrics.ResponseTimes.Max = meanDuration(func(rep *runner.Report) time.Duration { return rep.Metrics.ResponseTimes.Max })
+	report.Metrics.ResponseTimes.Mean = meanDuration(func(rep *runner.Report) time.Duration { return rep.Metrics.ResponseTimes.Mean })
+	report.Metadata.TotalDuration = meanDuration(func(rep *runner.Report) time.Duration { return rep.Metadata.TotalDuration })
+	report.Tests = testsuite.Run(cases, runs.Mean)
+	return &report
+}
@@ -2,19 +2,33 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/benchttp/engine/runner"
 
+	"github.com/benchttp/cli/internal/auth"
+	"github.com/benchttp/cli/internal/baseline"
 	"github.com/benchttp/cli/internal/configfile"
 	"github.com/benchttp/cli/internal/configflag"
+	"github.com/benchttp/cli/internal/dotenv"
+	"github.com/benchttp/cli/internal/history"
 	"github.com/benchttp/cli/internal/output"
+	"github.com/benchttp/cli/internal/redact"
 	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+	"github.com/benchttp/cli/internal/reportfile"
 	"github.com/benchttp/cli/internal/signals"
+	"github.com/benchttp/cli/internal/stats"
+	"github.com/benchttp/cli/internal/testsuite"
 )
 
 // cmdRun handles subcommand "benchttp run [options]".
@@ -24,9 +38,60 @@ type cmdRun struct {
 	// configFile is the parsed value for flag -configFile
 	configFile string
 
+	// configDir is the parsed value for flag -configDir
+	configDir string
+
+	// configPatterns are the config files and glob patterns passed
+	// as positional arguments
+	configPatterns []string
+
+	// failFast is the parsed value for flag -failFast
+	failFast bool
+
+	// scenario is the parsed value for flag -scenario
+	scenario string
+
+	// repeat is the parsed value for flag -repeat
+	repeat int
+
+	// reportFile is the parsed value for flag -report
+	reportFile string
+
+	// history is the parsed value for flag -history
+	history bool
+
+	// baselineFile is the parsed value for flag -baseline
+	baselineFile string
+
+	// updateBaseline is the parsed value for flag -updateBaseline
+	updateBaseline bool
+
+	// baseline is the report read from baselineFile, nil if it is not
+	// set or does not exist yet and updateBaseline is set
+	baseline *runner.Report
+
+	// envFile is the parsed value for flag -envFile
+	envFile string
+
 	// silent is the parsed value for flag -silent
 	silent bool
 
+	// insecure is the parsed value for flags -insecure and -k
+	insecure bool
+
+	// showSecrets is the parsed value for flag -showSecrets
+	showSecrets bool
+
+	// redactRules is the parsed value for flag -redact
+	redactRules redact.Rules
+
+	// auth is the parsed value for flags -basicAuth and -bearer
+	auth auth.Auth
+
+	// secrets are the credentials loaded from the env file and the
+	// resolved ones, masked in the rendered output.
+	secrets []string
+
 	// config is the runner config resulting from parsing CLI flags.
 	config runner.Config
 }
@@ -34,6 +99,9 @@ type cmdRun struct {
 // init initializes cmdRun with default values.
 func (cmd *cmdRun) init() {
 	cmd.config = runner.DefaultConfig()
+	// the default header is shared by all default configs: give the CLI
+	// config its own, as flag -header adds values to it
+	cmd.config.Request.Header = http.Header{}
 	cmd.configFile = configfile.Find([]string{
 		"./.benchttp.yml",
 		"./.benchttp.yaml",
@@ -43,32 +111,276 @@ func (cmd *cmdRun) init() {
 
 // execute runs the benchttp runner: it parses CLI flags, loads config
 // from config file and parsed flags, then runs the benchmark and outputs
-// it according to the config.
+// it according to the config. If several config files or scenarios are
+// given, their benchmarks are run sequentially.
 func (cmd *cmdRun) execute(args []string) error {
-	cmd.init()
+	fields, err := cmd.parseArgs(args)
+	if err != nil {
+		return err
+	}
 
-	// Generate merged config (default < config file < CLI flags)
-	cfg, err := cmd.makeConfig(args)
+	benchmarks, err := cmd.benchmarks(fields)
 	if err != nil {
 		return err
 	}
 
-	report, err := runBenchmark(cfg, cmd.silent)
+	if cmd.insecure {
+		skipTLSVerify()
+	}
+
+	// Prepare graceful shutdown in case of os.Interrupt (Ctrl+C)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go signals.ListenOSInterrupt(cancel)
+
+	if len(benchmarks) == 1 && !cmd.severalFiles() {
+		if err := cmd.readBaseline(); err != nil {
+			return err
+		}
+		_, err = cmd.runOne(ctx, benchmarks[0])
+		return err
+	}
+	if cmd.reportFile != "" {
+		return fmt.Errorf("%w: -report requires a single benchmark", errUsage)
+	}
+	if cmd.baselineFile != "" {
+		return fmt.Errorf("%w: -baseline requires a single benchmark", errUsage)
+	}
+	return cmd.runAll(ctx, benchmarks)
+}
+
+// benchmark is a benchmark to run, named after its config file
+// and scenario.
+type benchmark struct {
+	name   string
+	config runner.Config
+
+	// tolerances are the regressions allowed by its config file
+	// compared with the baseline
+	tolerances baseline.Tolerances
+
+	// relativeTests are the tests of its config file with a relative
+	// target, run by the CLI after the ones of the engine
+	relativeTests []testsuite.RelativeCase
+
+	// warnings are the names of the tests of its config file
+	// of severity warn, whose failures do not fail the run
+	warnings testsuite.Warnings
+
+	// err is the error resolving the config, if any
+	err error
+}
+
+// benchmarks returns the benchmarks to run: one per scenario, or one
+// per config file if it declares none. With -configDir and config file
+// arguments, the config errors are reported by the benchmarks of the
+// files, so that the other ones can still run.
+func (cmd *cmdRun) benchmarks(fields []string) ([]benchmark, error) {
+	if !cmd.severalFiles() {
+		return cmd.fileBenchmarks(fields, "")
+	}
+
+	filenames, err := cmd.configFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	all := []benchmark{}
+	for _, filename := range filenames {
+		cmd.configFile = filename
+		benchmarks, err := cmd.fileBenchmarks(fields, filename)
+		if err != nil {
+			benchmarks = []benchmark{{name: filename, err: err}}
+		}
+		all = append(all, benchmarks...)
+	}
+	return all, nil
+}
+
+// fileBenchmarks returns the benchmarks of cmd.configFile: one per
+// selected scenario, or a single one if it declares none. Their name
+// is prefixed with the given one.
+func (cmd *cmdRun) fileBenchmarks(fields []string, name string) ([]benchmark, error) {
+	file, err := cmd.resolveConfig(fields)
 	if err != nil {
+		return nil, err
+	}
+	if len(file.Scenarios) == 0 {
+		return []benchmark{{
+			name:          name,
+			config:        file.Config,
+			tolerances:    file.Tolerances,
+			relativeTests: file.RelativeTests,
+			warnings:      file.Warnings,
+		}}, nil
+	}
+
+	benchmarks := make([]benchmark, len(file.Scenarios))
+	for i, scenario := range file.Scenarios {
+		benchmarks[i] = benchmark{
+			name:          scenario.Name,
+			config:        scenario.Config,
+			tolerances:    file.Tolerances,
+			relativeTests: scenario.RelativeTests,
+			warnings:      scenario.Warnings,
+		}
+		if name != "" {
+			benchmarks[i].name = name + ":" + scenario.Name
+		}
+	}
+	return benchmarks, nil
+}
+
+// severalFiles returns true if the config files are given by -configDir
+// or positional arguments.
+func (cmd *cmdRun) severalFiles() bool {
+	return cmd.configDir != "" || len(cmd.configPatterns) != 0
+}
+
+// runOne runs the benchmark and renders its report, repeatedly if
+// -repeat is set. The returned report is nil if the benchmark could
+// not run.
+func (cmd *cmdRun) runOne(ctx context.Context, bench benchmark) (*runner.Report, error) {
+	if cmd.repeat > 1 {
+		return cmd.runRepeated(ctx, bench)
+	}
+
+	report, err := runBenchmark(ctx, bench.config, cmd.silent)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor := cmd.redactor()
+	report = redactor.Report(report)
+	cmd.runTests(bench, report, func(field runner.MetricsField) runner.MetricsValue {
+		return stats.Metric(report, field)
+	})
+
+	if err := cmd.saveReport(bench, report); err != nil {
+		return nil, err
+	}
+
+	return report, cmd.renderReport(redactor.Writer(os.Stdout), bench, report)
+}
+
+// runTests runs the tests of the benchmark with a relative target
+// against the metrics returned by metric and the baseline, and merges
+// their results into the ones of the report. The test suite passes
+// if no test failed but its warnings.
+func (cmd *cmdRun) runTests(bench benchmark, report *runner.Report, metric func(runner.MetricsField) runner.MetricsValue) {
+	if len(bench.relativeTests) != 0 {
+		report.Tests = testsuite.Merge(report.Tests, testsuite.RunRelative(bench.relativeTests, metric, cmd.baseline))
+	}
+	report.Tests = bench.warnings.Apply(report.Tests)
+}
+
+// saveReport writes the report of the benchmark to the file of flag
+// -report, to the baseline file if -updateBaseline is set, and to the
+// local history if -history is set.
+func (cmd *cmdRun) saveReport(bench benchmark, report *runner.Report) error {
+	if cmd.reportFile != "" {
+		if err := reportfile.Write(cmd.reportFile, report, bench.warnings); err != nil {
+			return err
+		}
+	}
+
+	if cmd.updateBaseline {
+		if err := reportfile.Write(cmd.baselineFile, report, bench.warnings); err != nil {
+			return err
+		}
+	}
+
+	if !cmd.history {
+		return nil
+	}
+	name := bench.name
+	if name == "" {
+		name = cmd.configFile
+	}
+	if name == "" {
+		name = bench.config.Request.URL.String()
+	}
+	_, err := history.Save(history.DefaultDir, history.Entry{
+		Name:       name,
+		ConfigHash: history.ConfigHash(bench.config),
+		Commit:     history.GitCommit(),
+		Time:       report.Metadata.FinishedAt,
+		Report:     report,
+		Warnings:   bench.warnings,
+	})
+	return err
+}
+
+// runAll runs the benchmarks sequentially, each in its own section,
+// then renders a table of their results. It fails if any benchmark
+// failed, after running all of them unless -failFast is set.
+func (cmd *cmdRun) runAll(ctx context.Context, benchmarks []benchmark) error {
+	stdout := output.ConditionalWriter{Writer: os.Stdout}.If(!cmd.silent)
+	results := make([]render.Benchmark, 0, len(benchmarks))
+	failed := 0
+
+	for i, bench := range benchmarks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		fmt.Fprintln(stdout, ansi.Bold(fmt.Sprintf("[%d/%d] %s", i+1, len(benchmarks), bench.name)))
+
+		report, err := (*runner.Report)(nil), bench.err
+		if err == nil {
+			report, err = cmd.runOne(ctx, bench)
+		}
+		results = append(results, render.Benchmark{Name: bench.name, Report: report})
+		if err != nil {
+			failed++
+			fmt.Fprintf(cmd.redactor().Writer(os.Stderr), "%s: %s\n", bench.name, err)
+			if cmd.failFast {
+				break
+			}
+		}
+		fmt.Fprintln(stdout)
+	}
+
+	if _, err := render.BenchmarksTable(cmd.redactor().Writer(stdout), results); err != nil {
 		return err
 	}
 
-	return renderReport(os.Stdout, report, cmd.silent)
+	if failed != 0 {
+		return fmt.Errorf("%d of %d benchmarks failed", failed, len(benchmarks))
+	}
+	return nil
 }
 
-// parseArgs parses input args as config fields and returns
-// a slice of fields that were set by the user.
-func (cmd *cmdRun) parseArgs(args []string) []string {
-	// skip parsing if no flags are provided
-	if len(args) == 0 {
-		return []string{}
+// configFiles returns the config files matched by -configDir and
+// the positional config arguments.
+func (cmd *cmdRun) configFiles() ([]string, error) {
+	if isFlagSet(cmd.flagset, "configFile") {
+		return nil, fmt.Errorf("%w: -configFile cannot be combined with -configDir or config file arguments", errUsage)
 	}
 
+	patterns := cmd.configPatterns
+	if cmd.configDir != "" {
+		patterns = append([]string{cmd.configDir}, patterns...)
+	}
+	return configfile.FindAll(patterns)
+}
+
+// redactor returns the *redact.Redactor applied to everything rendered,
+// or nil if flag -showSecrets is set.
+func (cmd *cmdRun) redactor() *redact.Redactor {
+	if cmd.showSecrets {
+		return nil
+	}
+	redactor := redact.New(cmd.redactRules)
+	redactor.AddValues(cmd.secrets...)
+	return redactor
+}
+
+// bindFlags initializes cmd with default values and binds its flags,
+// including the config fields, to its flagset.
+func (cmd *cmdRun) bindFlags() {
+	cmd.init()
+
 	// config file path
 	cmd.flagset.StringVar(&cmd.configFile,
 		"configFile",
@@ -76,6 +388,65 @@ func (cmd *cmdRun) parseArgs(args []string) []string {
 		"Config file path",
 	)
 
+	// several config files
+	cmd.flagset.StringVar(&cmd.configDir,
+		"configDir",
+		"",
+		"Directory of config files to run sequentially, one benchmark per file",
+	)
+	cmd.flagset.BoolVar(&cmd.failFast,
+		"failFast",
+		false,
+		"With several config files or scenarios, stop at the first failing benchmark",
+	)
+
+	// repeated runs
+	cmd.flagset.IntVar(&cmd.repeat,
+		"repeat",
+		1,
+		"Number of runs of each benchmark, reporting the spread of the metrics",
+	)
+
+	// report file
+	cmd.flagset.StringVar(&cmd.reportFile,
+		"report",
+		"",
+		"Write the report as JSON to file, e.g. for command compare",
+	)
+
+	// baseline comparison
+	cmd.flagset.StringVar(&cmd.baselineFile,
+		"baseline",
+		"",
+		"Compare the report with a baseline report file, failing on regressions beyond the tolerances of the config file",
+	)
+	cmd.flagset.BoolVar(&cmd.updateBaseline,
+		"updateBaseline",
+		false,
+		"Rewrite the file of -baseline with the report, without failing on regressions",
+	)
+
+	// local history
+	cmd.flagset.BoolVar(&cmd.history,
+		"history",
+		false,
+		"Save the report to the local history ("+history.DefaultDir+")",
+	)
+
+	// scenario selection
+	cmd.flagset.StringVar(&cmd.scenario,
+		"scenario",
+		"",
+		"Name of the scenario of the config file to run (default: all)",
+	)
+
+	// env file path
+	cmd.flagset.StringVar(&cmd.envFile,
+		"envFile",
+		"",
+		"Env file path, defaults to .env next to the config file",
+	)
+
 	// silent mode
 	cmd.flagset.BoolVar(&cmd.silent,
 		"silent",
@@ -83,38 +454,279 @@ func (cmd *cmdRun) parseArgs(args []string) []string {
 		"Silent mode",
 	)
 
+	// insecure mode
+	cmd.flagset.BoolVar(&cmd.insecure,
+		"insecure",
+		false,
+		"Skip TLS certificate verification",
+	)
+	cmd.flagset.BoolVar(&cmd.insecure, "k", false, "Alias for -insecure (curl)")
+
+	// secrets redaction
+	cmd.flagset.Var(&cmd.redactRules,
+		"redact",
+		`Redact sensitive values at "<header|query|body>:<pattern>"`,
+	)
+	cmd.flagset.BoolVar(&cmd.showSecrets,
+		"showSecrets",
+		false,
+		"Do not redact sensitive values from the output",
+	)
+
 	// attach config options flags to the flagset
 	// and bind their value to the config struct
 	configflag.Bind(cmd.flagset, &cmd.config)
+	configflag.BindAuth(cmd.flagset, &cmd.auth)
+}
+
+// parseArgs parses the flags bound by bindFlags, and the positional
+// arguments: config files and glob patterns, or the url.
+// It returns the config fields that were set.
+func (cmd *cmdRun) parseArgs(args []string) ([]string, error) {
+	// skip parsing if no flags are provided
+	if len(args) == 0 {
+		return []string{}, nil
+	}
 
 	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
 
-	return configflag.Which(cmd.flagset)
+	// like curl, accept the url as a positional argument
+	for cmd.flagset.NArg() != 0 {
+		rest := cmd.flagset.Args()
+		if isConfigPattern(rest[0]) {
+			cmd.configPatterns = append(cmd.configPatterns, rest[0])
+		} else if err := cmd.flagset.Set(runner.ConfigFieldURL, rest[0]); err != nil {
+			return nil, fmt.Errorf("%w: %s", errUsage, err)
+		}
+		cmd.flagset.Parse(rest[1:]) //nolint:errcheck // never occurs due to flag.ExitOnError
+	}
+
+	if cmd.repeat < 1 {
+		return nil, fmt.Errorf("%w: -repeat must be at least 1", errUsage)
+	}
+	// the report of repeated runs is a mix of the last one and of the
+	// means of a few metrics: it is not saved to be compared later
+	for _, name := range reportSavingFlags {
+		if cmd.repeat > 1 && isFlagSet(cmd.flagset, name) {
+			return nil, fmt.Errorf("%w: flag -%s is not supported with -repeat", errUsage, name)
+		}
+	}
+	if cmd.updateBaseline && cmd.baselineFile == "" {
+		return nil, fmt.Errorf("%w: -updateBaseline requires -baseline", errUsage)
+	}
+
+	return configflag.Which(cmd.flagset), nil
+}
+
+// reportSavingFlags lists the flags saving the report of a run,
+// that cannot be set with -repeat.
+var reportSavingFlags = []string{"report", "updateBaseline", "history"}
+
+// isConfigPattern returns true if the positional argument arg is a config
+// file path or glob pattern rather than a url.
+func isConfigPattern(arg string) bool {
+	return !strings.Contains(arg, "://") && contains(configfile.Extensions, filepath.Ext(arg))
 }
 
-// makeConfig returns a runner.ConfigGlobal initialized with config file
-// options if found, overridden with CLI options listed in fields
-// slice param.
-func (cmd *cmdRun) makeConfig(args []string) (cfg runner.Config, err error) {
+// makeConfig parses args and returns the config resolved from them.
+// A single config file can be given as a positional argument. If it
+// declares several scenarios, one must be selected with -scenario.
+func (cmd *cmdRun) makeConfig(args []string) (runner.Config, error) {
 	// Set CLI config from flags and retrieve fields that were set
-	fields := cmd.parseArgs(args)
+	fields, err := cmd.parseArgs(args)
+	if err != nil {
+		return runner.Config{}, err
+	}
+
+	if cmd.severalFiles() {
+		filenames, err := cmd.configFiles()
+		if err != nil {
+			return runner.Config{}, err
+		}
+		if len(filenames) != 1 {
+			return runner.Config{}, fmt.Errorf("%w: expected a single config file, got %d", errUsage, len(filenames))
+		}
+		cmd.configFile = filenames[0]
+	}
+
+	file, err := cmd.resolveConfig(fields)
+	switch {
+	case err != nil:
+		return runner.Config{}, err
+	case len(file.Scenarios) > 1:
+		return runner.Config{}, fmt.Errorf("%w: %s declares several scenarios, select one with -scenario", errUsage, cmd.configFile)
+	case len(file.Scenarios) == 1:
+		return file.Scenarios[0].Config, nil
+	default:
+		return file.Config, nil
+	}
+}
+
+// resolveConfig returns the parsed config file if found, its config
+// overridden with CLI options listed in fields slice param, or a file
+// of the CLI config only if not found.
+//
+// If the config file declares scenarios, they are overridden with the
+// CLI options as well: only the one of flag -scenario is kept if set.
+// The config of the file is then not validated, as it is not run.
+func (cmd *cmdRun) resolveConfig(fields []string) (configfile.File, error) {
+	// Load env file before the config file is parsed, so its variables
+	// are available for interpolation
+	env, err := cmd.loadEnvFile()
+	if err != nil {
+		return configfile.File{}, err
+	}
+
+	// Resolve credentials set via the CLI, that may reference
+	// variables of the env file
+	if !cmd.auth.IsZero() {
+		if err := cmd.resolveAuth(env); err != nil {
+			return configfile.File{}, err
+		}
+		fields = append(fields, runner.ConfigFieldHeader)
+	}
 
 	// configFile not set and default ones not found:
 	// skip the merge and return the cli config
 	if cmd.configFile == "" {
-		return cmd.config, cmd.config.Validate()
+		if cmd.scenario != "" {
+			return configfile.File{}, fmt.Errorf("%w: -scenario requires a config file", errUsage)
+		}
+		return configfile.File{Config: cmd.config}, cmd.config.Validate()
 	}
 
-	fileConfig, err := configfile.Parse(cmd.configFile)
+	file, err := configfile.ParseFileWithEnv(cmd.configFile, env.Lookup)
 	if err != nil && !errors.Is(err, configfile.ErrFileNotFound) {
 		// config file is not mandatory: discard ErrFileNotFound.
 		// other errors are critical
-		return
+		return configfile.File{}, err
 	}
+	cmd.secrets = append(cmd.secrets, file.Secrets...)
+	cmd.redactRules.Headers = append(cmd.redactRules.Headers, file.Redact.Headers...)
+	cmd.redactRules.Query = append(cmd.redactRules.Query, file.Redact.Query...)
+	cmd.redactRules.Body = append(cmd.redactRules.Body, file.Redact.Body...)
+
+	file.Config = cmd.override(fields, file.Config)
+	if len(file.Scenarios) == 0 {
+		if cmd.scenario != "" {
+			return configfile.File{}, fmt.Errorf("%w: %s declares no scenarios", errUsage, cmd.configFile)
+		}
+		return file, file.Config.Validate()
+	}
+
+	file.Scenarios, err = cmd.selectScenarios(file.Scenarios)
+	if err != nil {
+		return configfile.File{}, err
+	}
+	for i, scenario := range file.Scenarios {
+		file.Scenarios[i].Config = cmd.override(fields, scenario.Config)
+		if err := file.Scenarios[i].Config.Validate(); err != nil {
+			return configfile.File{}, fmt.Errorf("scenario %s: %w", scenario.Name, err)
+		}
+	}
+	return file, nil
+}
 
+// override returns fileConfig overridden with the CLI options listed
+// in fields.
+func (cmd *cmdRun) override(fields []string, fileConfig runner.Config) runner.Config {
 	mergedConfig := cmd.config.WithFields(fields...).Override(fileConfig)
 
-	return mergedConfig, mergedConfig.Validate()
+	// query params set via the CLI are already part of the CLI url if set,
+	// else they are added to the ones of the config file url
+	if !contains(fields, runner.ConfigFieldURL) {
+		mergedConfig.Request.URL = withQuery(mergedConfig.Request.URL, configflag.Query(cmd.flagset))
+	}
+
+	return mergedConfig
+}
+
+// selectScenarios returns the scenario of flag -scenario, or all
+// scenarios if it is not set.
+func (cmd *cmdRun) selectScenarios(scenarios []configfile.Scenario) ([]configfile.Scenario, error) {
+	if cmd.scenario == "" {
+		return scenarios, nil
+	}
+
+	names := make([]string, len(scenarios))
+	for i, scenario := range scenarios {
+		if scenario.Name == cmd.scenario {
+			return []configfile.Scenario{scenario}, nil
+		}
+		names[i] = scenario.Name
+	}
+	return nil, fmt.Errorf("%w: unknown scenario %s in %s, available: %s",
+		errUsage, cmd.scenario, cmd.configFile, strings.Join(names, ", "))
+}
+
+// resolveAuth resolves the credentials set via the CLI into the
+// Authorization header of the CLI config, completing the process
+// environment with env.
+func (cmd *cmdRun) resolveAuth(env dotenv.Env) error {
+	value, secrets, err := cmd.auth.Header(".", env.Lookup)
+	if err != nil {
+		return err
+	}
+	cmd.config.Request.Header[auth.HeaderKey] = []string{value}
+	cmd.secrets = append(cmd.secrets, secrets...)
+	return nil
+}
+
+// withQuery returns a copy of u with query params q added.
+func withQuery(u *url.URL, q url.Values) *url.URL {
+	if u == nil || len(q) == 0 {
+		return u
+	}
+	merged := *u
+	configflag.AddQuery(&merged, q)
+	return &merged
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// loadEnvFile returns the variables of the env file of the current
+// config file, that complete the process environment when resolving it.
+// They are not set in the process environment, so that each config file
+// run by the command only sees its own env file. The values of its
+// credentials are registered as secrets.
+// If flag -envFile is not set, it looks for a file ".env" next to the
+// config file, that is ignored if not found.
+func (cmd *cmdRun) loadEnvFile() (dotenv.Env, error) {
+	filename, required := cmd.envFile, true
+	if filename == "" {
+		if cmd.configFile == "" {
+			return nil, nil
+		}
+		filename, required = filepath.Join(filepath.Dir(cmd.configFile), ".env"), false
+	}
+
+	env, err := dotenv.Load(filename)
+	if err != nil {
+		if !required && errors.Is(err, dotenv.ErrFileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cmd.secrets = append(cmd.secrets, env.Secrets()...)
+	return env, nil
+}
+
+// skipTLSVerify disables the verification of TLS certificates for the
+// benchmarked requests, that are sent via http.DefaultTransport.
+func skipTLSVerify() {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // explicitly requested by the user
+		}
+	}
 }
 
 func onRecordingProgress(silent bool) func(runner.RecordingProgress) {
@@ -131,11 +743,7 @@ func onRecordingProgress(silent bool) func(runner.RecordingProgress) {
 	}
 }
 
-func runBenchmark(cfg runner.Config, silent bool) (*runner.Report, error) {
-	// Prepare graceful shutdown in case of os.Interrupt (Ctrl+C)
-	ctx, cancel := context.WithCancel(context.Background())
-	go signals.ListenOSInterrupt(cancel)
-
+func runBenchmark(ctx context.Context, cfg runner.Config, silent bool) (*runner.Report, error) {
 	// Run the benchmark
 	report, err := runner.
 		New(onRecordingProgress(silent)).
@@ -147,16 +755,39 @@ func runBenchmark(cfg runner.Config, silent bool) (*runner.Report, error) {
 	return report, nil
 }
 
-func renderReport(w io.Writer, report *runner.Report, silent bool) error {
-	writeIfNotSilent := output.ConditionalWriter{Writer: w}.If(!silent)
+// renderReport renders the summary of the report of the benchmark,
+// then its checks: see renderChecks.
+func (cmd *cmdRun) renderReport(w io.Writer, bench benchmark, report *runner.Report) error {
+	writeIfNotSilent := output.ConditionalWriter{Writer: w}.If(!cmd.silent)
 
 	if _, err := render.ReportSummary(writeIfNotSilent, report); err != nil {
 		return err
 	}
 
+	return cmd.renderChecks(w, bench, report)
+}
+
+// renderChecks renders the comparison of the report with the baseline,
+// if any, then the results of its tests. It returns an error if a test
+// failed or a metric regressed beyond its tolerance.
+func (cmd *cmdRun) renderChecks(w io.Writer, bench benchmark, report *runner.Report) error {
+	baselineErr := cmd.compareBaseline(w, bench, report)
+	if err := renderTestSuite(w, report, bench.warnings, cmd.silent); err != nil {
+		return err
+	}
+	return baselineErr
+}
+
+// renderTestSuite renders the results of the tests of report, even in
+// silent mode if they failed, and returns an error if they failed.
+// The failures of the warnings are rendered but not decisive.
+func renderTestSuite(w io.Writer, report *runner.Report, warnings testsuite.Warnings, silent bool) error {
+	writeIfNotSilent := output.ConditionalWriter{Writer: w}.If(!silent)
+
 	if _, err := render.TestSuite(
 		writeIfNotSilent.ElseIf(!report.Tests.Pass),
 		report.Tests,
+		warnings,
 	); err != nil {
 		return err
 	}
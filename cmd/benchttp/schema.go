@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/benchttp/cli/internal/configfile"
+)
+
+// cmdSchema handles subcommand "benchttp schema [-out file]".
+type cmdSchema struct {
+	flagset *flag.FlagSet
+
+	// out is the parsed value for flag -out
+	out string
+}
+
+// bindFlags binds the flags of the command to the flagset.
+func (cmd *cmdSchema) bindFlags() {
+	cmd.flagset.StringVar(&cmd.out,
+		"out",
+		"",
+		"Output file (default: stdout)",
+	)
+}
+
+// execute writes the JSON Schema of config files to the output file
+// or stdout.
+func (cmd *cmdSchema) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	if cmd.out == "" {
+		_, err := os.Stdout.Write(configfile.Schema)
+		return err
+	}
+	if err := os.WriteFile(cmd.out, configfile.Schema, 0o600); err != nil {
+		return err
+	}
+	fmt.Println("wrote", cmd.out)
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/output"
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+	"github.com/benchttp/cli/internal/signals"
+	"github.com/benchttp/cli/internal/sweep"
+)
+
+// cmdSweep handles subcommand "benchttp sweep [options]".
+type cmdSweep struct {
+	flagset *flag.FlagSet
+
+	// param is the parsed value for flag -param
+	param string
+
+	// values is the parsed value for flag -values
+	values string
+
+	// run resolves the config from the flags of command run
+	run cmdRun
+}
+
+// bindFlags binds the flags of command run and the sweep flags
+// to the flagset.
+func (cmd *cmdSweep) bindFlags() {
+	cmd.flagset.StringVar(&cmd.param,
+		"param",
+		runner.ConfigFieldConcurrency,
+		"Runner parameter to vary",
+	)
+	cmd.flagset.StringVar(&cmd.values,
+		"values",
+		"",
+		"Values of the parameter: a list (1,2,4) or a range (1..64*2, 10ms..100ms+10ms)",
+	)
+
+	cmd.run.flagset = cmd.flagset
+	cmd.run.bindFlags()
+}
+
+// execute runs the benchmark of the config resolved as for command run
+// once per value of the swept parameter, then renders the results.
+func (cmd *cmdSweep) execute(args []string) error {
+	cfg, err := cmd.run.makeConfig(args)
+	if err != nil {
+		return err
+	}
+	for _, name := range runOnlyFlags {
+		if isFlagSet(cmd.flagset, name) {
+			return fmt.Errorf("%w: flag -%s is not supported by command sweep", errUsage, name)
+		}
+	}
+	// the results are compared across values, not tested
+	cfg.Tests = nil
+
+	param, err := sweep.LookupParam(cmd.param)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	if cmd.values == "" {
+		return fmt.Errorf("%w: no values specified, use -values", errUsage)
+	}
+	values, err := param.ParseValues(cmd.values)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	// fail before running any benchmark if one of them cannot run,
+	// e.g. a concurrency above the number of requests
+	for _, v := range values {
+		if err := param.Set(cfg, v).Validate(); err != nil {
+			return fmt.Errorf("%s=%s: %w", param.Name, param.Format(v), err)
+		}
+	}
+
+	if cmd.run.insecure {
+		skipTLSVerify()
+	}
+
+	// Prepare graceful shutdown in case of os.Interrupt (Ctrl+C)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go signals.ListenOSInterrupt(cancel)
+
+	points, failed := cmd.sweep(ctx, cfg, param, values)
+
+	if _, err := render.Sweep(cmd.run.redactor().Writer(os.Stdout), param.Name, renderedPoints(param, points)); err != nil {
+		return err
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d benchmarks failed", failed, len(values))
+	}
+	return nil
+}
+
+// sweep runs the benchmark of cfg for each value of param in turn,
+// and returns their results and the number of failed benchmarks.
+func (cmd *cmdSweep) sweep(ctx context.Context, cfg runner.Config, param sweep.Param, values []int64) ([]sweep.Point, int) {
+	redactor := cmd.run.redactor()
+	stdout := output.ConditionalWriter{Writer: redactor.Writer(os.Stdout)}.If(!cmd.run.silent)
+	points := make([]sweep.Point, 0, len(values))
+	failed := 0
+
+	for i, v := range values {
+		if ctx.Err() != nil {
+			break
+		}
+
+		name := param.Name + "=" + param.Format(v)
+		fmt.Fprintln(stdout, ansi.Bold(fmt.Sprintf("[%d/%d] %s", i+1, len(values), name)))
+
+		report, err := runBenchmark(ctx, param.Set(cfg, v), cmd.run.silent)
+		if err != nil {
+			failed++
+			report = nil
+			fmt.Fprintf(redactor.Writer(os.Stderr), "%s: %s\n", name, err)
+		}
+		points = append(points, sweep.Point{Value: v, Report: report})
+		fmt.Fprintln(stdout)
+	}
+
+	return points, failed
+}
+
+// renderedPoints returns the points as rendered by render.Sweep.
+func renderedPoints(param sweep.Param, points []sweep.Point) []render.SweepPoint {
+	knee := sweep.Knee(points)
+	rendered := make([]render.SweepPoint, len(points))
+	for i, p := range points {
+		rendered[i] = render.SweepPoint{
+			Value:  param.Format(p.Value),
+			Failed: p.Report == nil,
+			Knee:   i == knee,
+		}
+		if p.Report == nil {
+			continue
+		}
+		rendered[i].Requests = len(p.Report.Metrics.Records)
+		rendered[i].Errors = len(p.Report.Metrics.RequestFailures)
+		rendered[i].Throughput = p.Throughput()
+		rendered[i].P50 = p.Latency(50)
+		rendered[i].P90 = p.Latency(90)
+		rendered[i].P99 = p.Latency(99)
+	}
+	return rendered
+}
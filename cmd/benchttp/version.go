@@ -1,6 +1,14 @@
 package main
 
-import "fmt"
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
 
 // benchttpVersion is the current version of benchttp
 // as output by `benchttp version`. It is assumed to be set
@@ -12,10 +20,114 @@ import "fmt"
 // is ran locally without a build (e.g. `go run ./cmd/benchttp`).
 var benchttpVersion = "development"
 
-// cmdVersion handles subcommand "benchttp version".
-type cmdVersion struct{}
+// benchttpBuildTime is the time of the build, in RFC 3339 format.
+// Like benchttpVersion, it is assumed to be set via -ldflags.
+// If it is not, the time of the VCS revision is reported instead.
+var benchttpBuildTime = ""
 
-func (cmdVersion) execute([]string) error {
-	fmt.Println("benchttp", benchttpVersion)
+// benchttpRevision is the VCS revision of the build. Like
+// benchttpVersion, it is assumed to be set via -ldflags, as the
+// toolchains prior to Go 1.18 do not embed it in the binary.
+var benchttpRevision = ""
+
+// engineModule is the path of the module of the benchmark engine.
+const engineModule = "github.com/benchttp/engine"
+
+// cmdVersion handles subcommand "benchttp version [-verbose|-json]".
+type cmdVersion struct {
+	flagset *flag.FlagSet
+
+	// verbose is the parsed value for flag -verbose
+	verbose bool
+
+	// json is the parsed value for flag -json
+	json bool
+}
+
+// buildInfo describes the build of the running benchttp binary.
+type buildInfo struct {
+	Version       string `json:"version"`
+	EngineVersion string `json:"engineVersion"`
+	Revision      string `json:"revision"`
+	BuildTime     string `json:"buildTime"`
+	Dirty         bool   `json:"dirty"`
+	GoVersion     string `json:"goVersion"`
+	Platform      string `json:"platform"`
+}
+
+// bindFlags binds the flags of the command to the flagset.
+func (cmd *cmdVersion) bindFlags() {
+	cmd.flagset.BoolVar(&cmd.verbose,
+		"verbose",
+		false,
+		"Print the details of the build",
+	)
+	cmd.flagset.BoolVar(&cmd.json,
+		"json",
+		false,
+		"Print the details of the build as JSON",
+	)
+}
+
+// execute prints the version of benchttp, and the details of the build
+// if -verbose or -json is set.
+func (cmd *cmdVersion) execute(args []string) error {
+	cmd.flagset.Parse(args) //nolint:errcheck // never occurs due to flag.ExitOnError
+
+	switch {
+	case cmd.json:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(readBuildInfo())
+	case cmd.verbose:
+		info := readBuildInfo()
+		dirty := ""
+		if info.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Println("benchttp", info.Version)
+		fmt.Printf("  engine:    %s\n", info.EngineVersion)
+		fmt.Printf("  revision:  %s%s\n", info.Revision, dirty)
+		fmt.Printf("  built:     %s\n", info.BuildTime)
+		fmt.Printf("  go:        %s %s\n", info.GoVersion, info.Platform)
+	default:
+		fmt.Println("benchttp", benchttpVersion)
+	}
 	return nil
 }
+
+// readBuildInfo returns the details of the build of the running binary,
+// as embedded by the Go toolchain and -ldflags. The values that are not
+// available, e.g. the revision when built outside of a repository,
+// are "unknown".
+func readBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:       benchttpVersion,
+		EngineVersion: "unknown",
+		Revision:      benchttpRevision,
+		BuildTime:     benchttpBuildTime,
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if dep.Path != engineModule {
+				continue
+			}
+			info.EngineVersion = dep.Version
+			if dep.Replace != nil {
+				info.EngineVersion = strings.TrimSpace(dep.Replace.Path + " " + dep.Replace.Version)
+			}
+		}
+		readVCSSettings(bi, &info)
+	}
+
+	if info.Revision == "" {
+		info.Revision = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
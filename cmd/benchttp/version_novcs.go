@@ -0,0 +1,10 @@
+//go:build !go1.18
+// +build !go1.18
+
+package main
+
+import "runtime/debug"
+
+// readVCSSettings does nothing: the toolchains prior to Go 1.18
+// do not embed VCS settings, only -ldflags can provide them.
+func readVCSSettings(_ *debug.BuildInfo, _ *buildInfo) {}
@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package main
+
+import "runtime/debug"
+
+// readVCSSettings completes info with the VCS settings embedded by the
+// toolchain since Go 1.18. The values set via -ldflags take precedence.
+func readVCSSettings(bi *debug.BuildInfo, info *buildInfo) {
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Revision == "" {
+				info.Revision = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			info.Dirty = setting.Value == "true"
+		}
+	}
+}
@@ -2,9 +2,14 @@ module github.com/benchttp/cli
 
 go 1.17
 
-require github.com/benchttp/engine v0.1.0
+require (
+	github.com/benchttp/engine v0.1.0
+	github.com/xeipuuv/gojsonschema v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
+	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
+	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
 	golang.org/x/sync v0.0.0-20220722155255-886fb9371eb4 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,124 @@
+// Package auth builds the Authorization header of the benchmarked request
+// from credentials that can be read from the environment or a file,
+// so they do not need to appear in the command line.
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// HeaderKey is the header set by Auth.
+const HeaderKey = "Authorization"
+
+// Type is an authentication scheme.
+type Type string
+
+const (
+	// Basic is the HTTP Basic authentication scheme.
+	Basic Type = "basic"
+	// Bearer is the Bearer token authentication scheme.
+	Bearer Type = "bearer"
+)
+
+var (
+	// ErrInvalidType signals an unknown authentication type.
+	ErrInvalidType = errors.New("invalid auth type")
+
+	// ErrMissingCredentials signals an incomplete auth configuration.
+	ErrMissingCredentials = errors.New("missing credentials")
+
+	// ErrSource signals a credential that could not be read from
+	// its source.
+	ErrSource = errors.New("cannot read credential")
+)
+
+// Source is a reference to a credential value. Its format is either:
+//
+//	env:NAME   the value of the environment variable NAME
+//	file:PATH  the content of the file at PATH, trailing newlines trimmed
+//	VALUE      the value itself
+type Source string
+
+// LookupEnv looks up the value of an environment variable,
+// like os.LookupEnv.
+type LookupEnv func(name string) (string, bool)
+
+// Resolve returns the value referenced by s. Relative file paths are
+// resolved from dir, environment variables with lookupEnv.
+func (s Source) Resolve(dir string, lookupEnv LookupEnv) (string, error) {
+	raw := string(s)
+	switch {
+	case strings.HasPrefix(raw, "env:"):
+		name := strings.TrimPrefix(raw, "env:")
+		val, ok := lookupEnv(name)
+		if !ok {
+			return "", errorutil.WithDetails(ErrSource, "environment variable not set", name)
+		}
+		return val, nil
+	case strings.HasPrefix(raw, "file:"):
+		path := strings.TrimPrefix(raw, "file:")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", errorutil.WithDetails(ErrSource, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	default:
+		return raw, nil
+	}
+}
+
+// Auth describes the credentials of the benchmarked request.
+type Auth struct {
+	Type Type
+	// Username is the user name for Basic authentication.
+	Username string
+	// Password is the source of the password for Basic authentication.
+	Password Source
+	// Token is the source of the token for Bearer authentication.
+	Token Source
+}
+
+// IsZero returns true if no authentication is configured.
+func (a Auth) IsZero() bool {
+	return a == Auth{}
+}
+
+// Header resolves the credentials and returns the value of the
+// Authorization header, along with the resolved secret values.
+// Relative file paths are resolved from dir, environment variables
+// with lookupEnv.
+func (a Auth) Header(dir string, lookupEnv LookupEnv) (value string, secrets []string, err error) {
+	switch a.Type {
+	case Basic:
+		if a.Username == "" {
+			return "", nil, errorutil.WithDetails(ErrMissingCredentials, "basic auth requires a username")
+		}
+		password, err := a.Password.Resolve(dir, lookupEnv)
+		if err != nil {
+			return "", nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(a.Username + ":" + password))
+		return "Basic " + encoded, []string{password, encoded}, nil
+	case Bearer:
+		token, err := a.Token.Resolve(dir, lookupEnv)
+		if err != nil {
+			return "", nil, err
+		}
+		if token == "" {
+			return "", nil, errorutil.WithDetails(ErrMissingCredentials, "bearer auth requires a token")
+		}
+		return "Bearer " + token, []string{token}, nil
+	default:
+		return "", nil, errorutil.WithDetails(ErrInvalidType, fmt.Sprintf(`%q (want "basic" or "bearer")`, a.Type))
+	}
+}
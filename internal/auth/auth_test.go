@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/benchttp/cli/internal/auth"
+)
+
+func TestAuth_Header(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token.txt"), []byte("filetoken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AUTH_TEST_PASSWORD", "envpass")
+
+	t.Run("return header value and secrets", func(t *testing.T) {
+		testcases := []struct {
+			label      string
+			auth       auth.Auth
+			expValue   string
+			expSecrets []string
+		}{
+			{
+				label:      "basic literal password",
+				auth:       auth.Auth{Type: auth.Basic, Username: "alice", Password: "pass"},
+				expValue:   "Basic YWxpY2U6cGFzcw==",
+				expSecrets: []string{"pass", "YWxpY2U6cGFzcw=="},
+			},
+			{
+				label:      "basic password from env",
+				auth:       auth.Auth{Type: auth.Basic, Username: "alice", Password: "env:AUTH_TEST_PASSWORD"},
+				expValue:   "Basic YWxpY2U6ZW52cGFzcw==",
+				expSecrets: []string{"envpass", "YWxpY2U6ZW52cGFzcw=="},
+			},
+			{
+				label:      "bearer token from relative file",
+				auth:       auth.Auth{Type: auth.Bearer, Token: "file:token.txt"},
+				expValue:   "Bearer filetoken",
+				expSecrets: []string{"filetoken"},
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.label, func(t *testing.T) {
+				value, secrets, err := tc.auth.Header(dir, os.LookupEnv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if value != tc.expValue {
+					t.Errorf("value: exp %q, got %q", tc.expValue, value)
+				}
+				if !reflect.DeepEqual(secrets, tc.expSecrets) {
+					t.Errorf("secrets: exp %q, got %q", tc.expSecrets, secrets)
+				}
+			})
+		}
+	})
+
+	t.Run("return errors", func(t *testing.T) {
+		testcases := []struct {
+			label  string
+			auth   auth.Auth
+			expErr error
+		}{
+			{
+				label:  "unknown type",
+				auth:   auth.Auth{Type: "digest"},
+				expErr: auth.ErrInvalidType,
+			},
+			{
+				label:  "basic without username",
+				auth:   auth.Auth{Type: auth.Basic, Password: "pass"},
+				expErr: auth.ErrMissingCredentials,
+			},
+			{
+				label:  "bearer without token",
+				auth:   auth.Auth{Type: auth.Bearer},
+				expErr: auth.ErrMissingCredentials,
+			},
+			{
+				label:  "unset env variable",
+				auth:   auth.Auth{Type: auth.Bearer, Token: "env:AUTH_TEST_UNSET"},
+				expErr: auth.ErrSource,
+			},
+			{
+				label:  "missing file",
+				auth:   auth.Auth{Type: auth.Bearer, Token: "file:nope.txt"},
+				expErr: auth.ErrSource,
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.label, func(t *testing.T) {
+				if _, _, err := tc.auth.Header(dir, os.LookupEnv); !errors.Is(err, tc.expErr) {
+					t.Errorf("\nexp %v\ngot %v", tc.expErr, err)
+				}
+			})
+		}
+	})
+}
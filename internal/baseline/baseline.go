@@ -0,0 +1,188 @@
+// Package baseline compares the metrics of a report with the ones of
+// a baseline report, and detects the regressions beyond the tolerances
+// allowed for each metric.
+package baseline
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/errorutil"
+	"github.com/benchttp/cli/internal/stats"
+)
+
+// ErrTolerance signals an invalid tolerance.
+var ErrTolerance = errors.New("invalid tolerance")
+
+// DefaultFields lists the metrics compared with the baseline even
+// without a tolerance: the ones of the report summary.
+var DefaultFields = []runner.MetricsField{
+	"RequestCount",
+	"RequestFailureCount",
+	"ResponseTimes.Min",
+	"ResponseTimes.Max",
+	"ResponseTimes.Mean",
+}
+
+// higherIsBetter lists the metrics that regress when they decrease.
+// The other ones regress when they increase.
+var higherIsBetter = []runner.MetricsField{
+	"RequestCount",
+	"RequestSuccessCount",
+}
+
+// Tolerance is the regression allowed for a metric compared to its
+// baseline value.
+type Tolerance struct {
+	// Relative is the allowed regression relative to the baseline
+	// value, e.g. 0.1 for 10%. It is ignored if Absolute is set.
+	Relative float64
+
+	// Absolute is the allowed regression, of the type of the metric:
+	// an int or a time.Duration.
+	Absolute runner.MetricsValue
+}
+
+// String returns the tolerance as written in config files,
+// e.g. "10%" or "50ms".
+func (t Tolerance) String() string {
+	if t.Absolute != nil {
+		return fmt.Sprint(t.Absolute)
+	}
+	return strconv.FormatFloat(t.Relative*100, 'f', -1, 64) + "%"
+}
+
+// Tolerances maps metric fields to their tolerance.
+type Tolerances map[runner.MetricsField]Tolerance
+
+// lookup returns the tolerance of field, its case being ignored
+// as for the engine fields.
+func (tolerances Tolerances) lookup(field runner.MetricsField) (Tolerance, bool) {
+	for f, t := range tolerances {
+		if strings.EqualFold(string(f), string(field)) {
+			return t, true
+		}
+	}
+	return Tolerance{}, false
+}
+
+// ParseTolerance parses the tolerance v of the given metric field:
+// a percentage such as "10%", or an absolute value of the type of the
+// metric, such as "50ms" for response times or 2 for counts.
+func ParseTolerance(field runner.MetricsField, v interface{}) (Tolerance, error) {
+	typ, err := stats.FieldType(field)
+	if err != nil {
+		return Tolerance{}, errorutil.WithDetails(ErrTolerance, err)
+	}
+
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || percent < 0 {
+			return Tolerance{}, errorutil.WithDetails(ErrTolerance, field, fmt.Sprintf("invalid percentage %q", s))
+		}
+		return Tolerance{Relative: percent / 100}, nil
+	}
+
+	if typ == "int" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return Tolerance{}, errorutil.WithDetails(ErrTolerance, field, fmt.Sprintf("want a percentage or a positive integer, got %q", s))
+		}
+		return Tolerance{Absolute: n}, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return Tolerance{}, errorutil.WithDetails(ErrTolerance, field, fmt.Sprintf("want a percentage or a positive duration, got %q", s))
+	}
+	return Tolerance{Absolute: d}, nil
+}
+
+// Metric is a metric of a report compared to its baseline value.
+type Metric struct {
+	Field runner.MetricsField
+
+	// Baseline and Current are the values of the metric in the
+	// baseline and the compared report.
+	Baseline, Current runner.MetricsValue
+
+	// Tolerance is the tolerance of the metric, nil if it has none.
+	Tolerance *Tolerance
+
+	// Regressed is true if the metric regressed beyond its tolerance.
+	Regressed bool
+}
+
+// Compare compares the metrics of current with the ones of base:
+// DefaultFields, then the other fields of tolerances in alphabetical
+// order. Only the metrics with a tolerance can regress.
+func Compare(base, current *runner.Report, tolerances Tolerances) []Metric {
+	fields := append([]runner.MetricsField{}, DefaultFields...)
+	extra := []runner.MetricsField{}
+	for field := range tolerances {
+		if !containsField(fields, field) {
+			extra = append(extra, field)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	fields = append(fields, extra...)
+
+	metrics := make([]Metric, len(fields))
+	for i, field := range fields {
+		m := Metric{
+			Field:    field,
+			Baseline: stats.Metric(base, field),
+			Current:  stats.Metric(current, field),
+		}
+		if t, ok := tolerances.lookup(field); ok {
+			m.Tolerance = &t
+			m.Regressed = regressed(field, toFloat(m.Baseline), toFloat(m.Current), t)
+		}
+		metrics[i] = m
+	}
+	return metrics
+}
+
+// Regressed returns true if any of metrics regressed.
+func Regressed(metrics []Metric) bool {
+	for _, m := range metrics {
+		if m.Regressed {
+			return true
+		}
+	}
+	return false
+}
+
+// regressed returns true if the value of field regressed from base
+// to current beyond tolerance t.
+func regressed(field runner.MetricsField, base, current float64, t Tolerance) bool {
+	allowed := base * t.Relative
+	if t.Absolute != nil {
+		allowed = toFloat(t.Absolute)
+	}
+	if containsField(higherIsBetter, field) {
+		return current < base-allowed
+	}
+	return current > base+allowed
+}
+
+// toFloat returns v, an int or a time.Duration, as a float64.
+func toFloat(v runner.MetricsValue) float64 {
+	f, _ := stats.Float(v)
+	return f
+}
+
+func containsField(fields []runner.MetricsField, field runner.MetricsField) bool {
+	for _, f := range fields {
+		if strings.EqualFold(string(f), string(field)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package baseline_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/baseline"
+)
+
+func TestParseTolerance(t *testing.T) {
+	t.Run("parse valid tolerances", func(t *testing.T) {
+		for _, tc := range []struct {
+			field runner.MetricsField
+			in    interface{}
+			exp   baseline.Tolerance
+		}{
+			{field: "ResponseTimes.Mean", in: "10%", exp: baseline.Tolerance{Relative: 0.1}},
+			{field: "ResponseTimes.Max", in: "50ms", exp: baseline.Tolerance{Absolute: 50 * time.Millisecond}},
+			{field: "RequestFailureCount", in: 2, exp: baseline.Tolerance{Absolute: 2}},
+			{field: "RequestFailureCount", in: "0", exp: baseline.Tolerance{Absolute: 0}},
+			{field: "StatusCodesDistribution.500", in: "1", exp: baseline.Tolerance{Absolute: 1}},
+			{field: "ResponseTimes.P99", in: "5ms", exp: baseline.Tolerance{Absolute: 5 * time.Millisecond}},
+		} {
+			got, err := baseline.ParseTolerance(tc.field, tc.in)
+			if err != nil {
+				t.Fatalf("%s %v: %v", tc.field, tc.in, err)
+			}
+			if got != tc.exp {
+				t.Errorf("%s %v: exp %#v, got %#v", tc.field, tc.in, tc.exp, got)
+			}
+		}
+	})
+
+	t.Run("return ErrTolerance for invalid tolerances", func(t *testing.T) {
+		for _, tc := range []struct {
+			field runner.MetricsField
+			in    interface{}
+		}{
+			{field: "ResponseTimes.Nope", in: "10%"},
+			{field: "ResponseTimes.Mean", in: "-10%"},
+			{field: "ResponseTimes.Mean", in: 3},
+			{field: "RequestFailureCount", in: "3ms"},
+			{field: "StatusCodesDistribution", in: 1},
+		} {
+			if _, err := baseline.ParseTolerance(tc.field, tc.in); !errors.Is(err, baseline.ErrTolerance) {
+				t.Errorf("%s %v: exp ErrTolerance, got %v", tc.field, tc.in, err)
+			}
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	base := newReport(10, 0, 100*time.Millisecond)
+	current := newReport(8, 1, 115*time.Millisecond)
+
+	metrics := baseline.Compare(base, current, baseline.Tolerances{
+		"ResponseTimes.Mean":          {Relative: 0.1},
+		"responsetimes.max":           {Absolute: 20 * time.Millisecond},
+		"RequestCount":                {Relative: 0.25},
+		"StatusCodesDistribution.500": {Absolute: 0},
+	})
+
+	exp := []struct {
+		field     runner.MetricsField
+		baseline  runner.MetricsValue
+		current   runner.MetricsValue
+		tolerance bool
+		regressed bool
+	}{
+		{"RequestCount", 10, 8, true, false},
+		{"RequestFailureCount", 0, 1, false, false},
+		{"ResponseTimes.Min", 100 * time.Millisecond, 115 * time.Millisecond, false, false},
+		{"ResponseTimes.Max", 100 * time.Millisecond, 115 * time.Millisecond, true, false},
+		{"ResponseTimes.Mean", 100 * time.Millisecond, 115 * time.Millisecond, true, true},
+		{"StatusCodesDistribution.500", 0, 0, true, false},
+	}
+	if len(metrics) != len(exp) {
+		t.Fatalf("exp %d metrics, got %d: %v", len(exp), len(metrics), metrics)
+	}
+	for i, m := range metrics {
+		e := exp[i]
+		if m.Field != e.field || m.Baseline != e.baseline || m.Current != e.current ||
+			(m.Tolerance != nil) != e.tolerance || m.Regressed != e.regressed {
+			t.Errorf("metric %d: exp %v, got %+v", i, e, m)
+		}
+	}
+
+	if !baseline.Regressed(metrics) {
+		t.Error("exp regression")
+	}
+	if baseline.Regressed(baseline.Compare(base, base, baseline.Tolerances{"ResponseTimes.Mean": {}})) {
+		t.Error("exp no regression against itself")
+	}
+}
+
+// newReport returns a report of n requests of the given response time,
+// failures of them failing.
+func newReport(n, failures int, responseTime time.Duration) *runner.Report {
+	rep := &runner.Report{}
+	for i := 0; i < n; i++ {
+		rep.Metrics.Records = append(rep.Metrics.Records, struct{ ResponseTime time.Duration }{responseTime})
+	}
+	for i := 0; i < failures; i++ {
+		rep.Metrics.RequestFailures = append(rep.Metrics.RequestFailures, struct{ Reason string }{"timeout"})
+	}
+	rep.Metrics.ResponseTimes.Min = responseTime
+	rep.Metrics.ResponseTimes.Max = responseTime
+	rep.Metrics.ResponseTimes.Mean = responseTime
+	rep.Metrics.StatusCodesDistribution = map[int]int{200: n - failures}
+	return rep
+}
@@ -0,0 +1,165 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Bash returns the bash completion script of p, to be sourced
+// or installed in the bash-completion directory.
+func Bash(p Program) []byte {
+	fn := "_" + identifier(p.Name)
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "# bash completion for %s, generated by %q.\n\n", p.Name, p.Name+" completion bash")
+
+	// file completion helper, restricted to the given extensions if any
+	fmt.Fprintf(&b, `%s_files() {
+    local ext
+    compopt -o filenames 2>/dev/null
+    COMPREPLY=($(compgen -d -- "$cur"))
+    if [[ $# -eq 0 ]]; then
+        COMPREPLY+=($(compgen -f -- "$cur"))
+        return
+    fi
+    for ext in "$@"; do
+        COMPREPLY+=($(compgen -f -X "!*$ext" -- "$cur"))
+    done
+}
+
+`, fn)
+
+	fmt.Fprintf(&b, `%s() {
+    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+    local flags valueflags
+
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+        return
+    fi
+
+    # -flag=value is split on "=" by bash
+    if [[ $cur == = ]]; then
+        cur=
+    elif [[ $prev == = ]]; then
+        prev=${COMP_WORDS[COMP_CWORD-2]}
+    fi
+
+    case ${COMP_WORDS[1]} in
+`, fn, strings.Join(p.commandNames(), " "))
+
+	for _, cmd := range p.Commands {
+		writeBashFlags(&b, fn, cmd)
+	}
+
+	fmt.Fprintf(&b, `    *)
+        return
+        ;;
+    esac
+
+    if [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+        return
+    fi
+    if [[ " $valueflags " == *" $prev "* ]]; then
+        return
+    fi
+
+    # index of the completed positional argument
+    local i pos=0
+    for ((i = 2; i < COMP_CWORD; i++)); do
+        case ${COMP_WORDS[i]} in
+        =)
+            ((i++))
+            ;;
+        -*)
+            if [[ ${COMP_WORDS[i+1]} != = && " $valueflags " == *" ${COMP_WORDS[i]} "* ]]; then
+                ((i++))
+            fi
+            ;;
+        *)
+            ((pos++))
+            ;;
+        esac
+    done
+
+    case ${COMP_WORDS[1]} in
+`)
+
+	for _, cmd := range p.Commands {
+		writeBashArgs(&b, fn, cmd)
+	}
+
+	fmt.Fprintf(&b, `    esac
+}
+
+complete -F %s %s
+`, fn, p.Name)
+
+	return b.Bytes()
+}
+
+// writeBashFlags writes the case of cmd that sets its flags and
+// completes their values.
+func writeBashFlags(b *bytes.Buffer, fn string, cmd Command) {
+	fmt.Fprintf(b, "    %s)\n", strings.Join(cmd.names(), "|"))
+	fmt.Fprintf(b, "        flags=%q\n", strings.Join(cmd.flagNames(), " "))
+	fmt.Fprintf(b, "        valueflags=%q\n", strings.Join(cmd.valueFlags(), " "))
+
+	// group the flags completed the same way
+	type group struct {
+		names  []string
+		action string
+	}
+	var groups []*group
+	byAction := map[string]*group{}
+	for _, f := range cmd.Flags {
+		if f.Bool || f.Value.isZero() {
+			continue
+		}
+		action := bashAction(fn, f.Value)
+		if g, ok := byAction[action]; ok {
+			g.names = append(g.names, "-"+f.Name)
+			continue
+		}
+		g := &group{names: []string{"-" + f.Name}, action: action}
+		byAction[action] = g
+		groups = append(groups, g)
+	}
+
+	if len(groups) != 0 {
+		b.WriteString("        case $prev in\n")
+		for _, g := range groups {
+			fmt.Fprintf(b, "        %s)\n            %s\n            return\n            ;;\n",
+				strings.Join(g.names, "|"), g.action)
+		}
+		b.WriteString("        esac\n")
+	}
+	b.WriteString("        ;;\n")
+}
+
+// writeBashArgs writes the case of cmd that completes its positional
+// arguments, if any.
+func writeBashArgs(b *bytes.Buffer, fn string, cmd Command) {
+	if cmd.Arg.isZero() && cmd.Rest.isZero() {
+		return
+	}
+	fmt.Fprintf(b, "    %s)\n", strings.Join(cmd.names(), "|"))
+	b.WriteString("        case $pos in\n")
+	if !cmd.Arg.isZero() {
+		fmt.Fprintf(b, "        0)\n            %s\n            ;;\n", bashAction(fn, cmd.Arg))
+	}
+	if !cmd.Rest.isZero() {
+		fmt.Fprintf(b, "        *)\n            %s\n            ;;\n", bashAction(fn, cmd.Rest))
+	}
+	b.WriteString("        esac\n        ;;\n")
+}
+
+// bashAction returns the bash statement completing v.
+func bashAction(fn string, v Values) string {
+	if v.Files {
+		return strings.TrimSpace(fn + "_files " + strings.Join(v.Extensions, " "))
+	}
+	return fmt.Sprintf(`COMPREPLY=($(compgen -W %q -- "$cur"))`, strings.Join(v.Choices, " "))
+}
@@ -0,0 +1,134 @@
+// Package completion generates shell completion scripts for a program
+// made of subcommands accepting Go-style flags (-name value).
+//
+// The scripts are generated from a description of the commands, so that
+// they stay in sync with the flags actually defined.
+package completion
+
+import (
+	"sort"
+	"strings"
+)
+
+// Program describes the completed program.
+type Program struct {
+	// Name is the name of the executable.
+	Name string
+
+	// Commands are the subcommands of the program, in order of listing.
+	Commands []Command
+}
+
+// Command describes a subcommand.
+type Command struct {
+	// Name is the name of the command.
+	Name string
+
+	// Aliases are alternative names of the command, not listed
+	// but completed the same way.
+	Aliases []string
+
+	// Description is a one-line description of the command.
+	Description string
+
+	// Flags are the flags accepted by the command.
+	Flags []Flag
+
+	// Arg is the completion of the first positional argument.
+	Arg Values
+
+	// Rest is the completion of the following positional arguments.
+	Rest Values
+}
+
+// Flag describes a flag of a command.
+type Flag struct {
+	// Name is the name of the flag, without the leading dash.
+	Name string
+
+	// Usage is a one-line description of the flag.
+	Usage string
+
+	// Bool is true for flags that take no value.
+	Bool bool
+
+	// Value is the completion of the value of the flag.
+	Value Values
+}
+
+// Values describes the completion of a value: a list of choices,
+// or the names of files.
+type Values struct {
+	// Choices are the accepted values.
+	Choices []string
+
+	// Files enables the completion of file names.
+	Files bool
+
+	// Extensions restricts the completed file names to the ones
+	// with these extensions (".yml"), if not empty.
+	Extensions []string
+}
+
+// isZero returns true if v completes nothing.
+func (v Values) isZero() bool {
+	return len(v.Choices) == 0 && !v.Files
+}
+
+// names returns the name and the aliases of cmd.
+func (cmd Command) names() []string {
+	return append([]string{cmd.Name}, cmd.Aliases...)
+}
+
+// valueFlags returns the names of the flags of cmd that take a value,
+// with their leading dash.
+func (cmd Command) valueFlags() []string {
+	names := []string{}
+	for _, f := range cmd.Flags {
+		if !f.Bool {
+			names = append(names, "-"+f.Name)
+		}
+	}
+	return names
+}
+
+// flagNames returns the names of the flags of cmd, with their
+// leading dash.
+func (cmd Command) flagNames() []string {
+	names := make([]string, len(cmd.Flags))
+	for i, f := range cmd.Flags {
+		names[i] = "-" + f.Name
+	}
+	return names
+}
+
+// commandNames returns the names of the commands of p, without aliases.
+func (p Program) commandNames() []string {
+	names := make([]string, len(p.Commands))
+	for i, cmd := range p.Commands {
+		names[i] = cmd.Name
+	}
+	return names
+}
+
+// identifier returns s with the characters that are not allowed
+// in shell function names replaced by underscores.
+func identifier(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// trimExtensions returns the extensions without their leading dot,
+// sorted.
+func trimExtensions(extensions []string) []string {
+	trimmed := make([]string, len(extensions))
+	for i, ext := range extensions {
+		trimmed[i] = strings.TrimPrefix(ext, ".")
+	}
+	sort.Strings(trimmed)
+	return trimmed
+}
@@ -0,0 +1,140 @@
+package completion_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/completion"
+)
+
+var program = completion.Program{
+	Name: "prog",
+	Commands: []completion.Command{
+		{
+			Name:        "run",
+			Aliases:     []string{"r"},
+			Description: "Run it",
+			Flags: []completion.Flag{
+				{Name: "configFile", Usage: "Config file path", Value: completion.Values{Files: true, Extensions: []string{".yml", ".json"}}},
+				{Name: "method", Usage: "HTTP method", Value: completion.Values{Choices: []string{"GET", "POST"}}},
+				{Name: "requests", Usage: "Number of requests"},
+				{Name: "silent", Usage: "Silent mode", Bool: true},
+			},
+		},
+		{
+			Name:        "import",
+			Description: "Import [things] from 'elsewhere'",
+			Arg:         completion.Values{Choices: []string{"curl", "har"}},
+			Rest:        completion.Values{Files: true, Extensions: []string{".har"}},
+		},
+	},
+}
+
+func TestBash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found")
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.yml", "b.json", "c.txt", "d.har"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script := filepath.Join(dir, "prog.bash")
+	if err := os.WriteFile(script, completion.Bash(program), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		line string
+		exp  string
+	}{
+		{line: "prog ", exp: "run import"},
+		{line: "prog r -", exp: "-configFile -method -requests -silent"},
+		{line: "prog run -method P", exp: "POST"},
+		{line: "prog run -method=", exp: "GET POST"},
+		{line: "prog run -configFile ", exp: "a.yml b.json"},
+		{line: "prog run -requests ", exp: ""},
+		{line: "prog import ", exp: "curl har"},
+		{line: "prog import har ", exp: "d.har"},
+		{line: "prog import -unknown har ", exp: "d.har"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			// split the line as bash does, "=" being a word break
+			words := strings.Fields(strings.ReplaceAll(tc.line, "=", " = "))
+			if strings.HasSuffix(tc.line, " ") || strings.HasSuffix(tc.line, "=") {
+				words = append(words, "")
+			}
+			quoted := make([]string, len(words))
+			for i, w := range words {
+				quoted[i] = "'" + w + "'"
+			}
+
+			cmd := exec.Command(bash, "-c", `source `+script+`
+				COMP_WORDS=(`+strings.Join(quoted, " ")+`)
+				COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+				_prog
+				printf '%s\n' "${COMPREPLY[@]}" | sort | xargs`)
+			cmd.Dir = dir
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("%v: %s", err, out)
+			}
+
+			exp := strings.Fields(tc.exp)
+			sort.Strings(exp)
+			got := strings.Fields(string(out))
+			if strings.Join(exp, " ") != strings.Join(got, " ") {
+				t.Errorf("exp %v, got %v", exp, got)
+			}
+		})
+	}
+}
+
+func TestZsh(t *testing.T) {
+	script := string(completion.Zsh(program))
+
+	for _, exp := range []string{
+		"#compdef prog\n",
+		`'import:Import [things] from '\''elsewhere'\'''`,
+		"    run|r)\n",
+		`'*-configFile[Config file path]:configFile:_files -g "*.(json|yml)"'`,
+		`'*-method[HTTP method]:method:(GET POST)'`,
+		`'*-requests[Number of requests]:requests: '`,
+		"'*-silent[Silent mode]'\n",
+		`'1:argument:(curl har)'`,
+		`'*:argument:_files -g "*.(har)"'`,
+		"compdef _prog prog\n",
+	} {
+		if !strings.Contains(script, exp) {
+			t.Errorf("missing %s in script:\n%s", exp, script)
+		}
+	}
+}
+
+func TestFish(t *testing.T) {
+	script := string(completion.Fish(program))
+
+	for _, exp := range []string{
+		"complete -c prog -f\n",
+		`complete -c prog -n __fish_use_subcommand -a import -d 'Import [things] from \'elsewhere\''`,
+		`complete -c prog -n '__prog_using_command run r' -o configFile -d 'Config file path' -x -a '(__fish_complete_suffix .yml; __fish_complete_suffix .json)'`,
+		`complete -c prog -n '__prog_using_command run r' -o method -d 'HTTP method' -x -a 'GET POST'`,
+		`complete -c prog -n '__prog_using_command run r' -o requests -d 'Number of requests' -x` + "\n",
+		`complete -c prog -n '__prog_using_command run r' -o silent -d 'Silent mode'` + "\n",
+		`complete -c prog -n '__prog_using_command import; and not __fish_seen_subcommand_from curl har' -a 'curl har'`,
+		`complete -c prog -n '__prog_using_command import; and __fish_seen_subcommand_from curl har' -a '(__fish_complete_suffix .har)'`,
+	} {
+		if !strings.Contains(script, exp) {
+			t.Errorf("missing %s in script:\n%s", exp, script)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Fish returns the fish completion script of p, to be sourced or
+// installed as <name>.fish in ~/.config/fish/completions.
+func Fish(p Program) []byte {
+	fn := "__" + identifier(p.Name)
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "# fish completion for %s, generated by %q.\n\n", p.Name, p.Name+" completion fish")
+
+	fmt.Fprintf(&b, `function %s_using_command
+    set -l tokens (commandline -opc)
+    test (count $tokens) -ge 2; and contains -- $tokens[2] $argv
+end
+
+`, fn)
+
+	fmt.Fprintf(&b, "complete -c %s -f\n", p.Name)
+	for _, cmd := range p.Commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n",
+			p.Name, cmd.Name, fishQuote(cmd.Description))
+	}
+
+	for _, cmd := range p.Commands {
+		b.WriteString("\n")
+		using := fmt.Sprintf("%s_using_command %s", fn, strings.Join(cmd.names(), " "))
+
+		for _, f := range cmd.Flags {
+			fmt.Fprintf(&b, "complete -c %s -n %s -o %s -d %s",
+				p.Name, fishQuote(using), f.Name, fishQuote(f.Usage))
+			if !f.Bool {
+				fmt.Fprintf(&b, " -x%s", fishArguments(f.Value))
+			}
+			b.WriteString("\n")
+		}
+
+		// the first argument is completed until one of its choices
+		// is seen, the following ones after
+		condition, restCondition := using, using
+		if len(cmd.Arg.Choices) != 0 {
+			seen := "__fish_seen_subcommand_from " + strings.Join(cmd.Arg.Choices, " ")
+			condition += "; and not " + seen
+			restCondition += "; and " + seen
+		}
+		if !cmd.Arg.isZero() {
+			fmt.Fprintf(&b, "complete -c %s -n %s%s\n", p.Name, fishQuote(condition), fishArguments(cmd.Arg))
+		}
+		if !cmd.Rest.isZero() {
+			fmt.Fprintf(&b, "complete -c %s -n %s%s\n", p.Name, fishQuote(restCondition), fishArguments(cmd.Rest))
+		}
+	}
+
+	return b.Bytes()
+}
+
+// fishArguments returns the options of complete that complete v.
+func fishArguments(v Values) string {
+	switch {
+	case v.Files && len(v.Extensions) != 0:
+		calls := make([]string, len(v.Extensions))
+		for i, ext := range v.Extensions {
+			calls[i] = "__fish_complete_suffix " + ext
+		}
+		return " -a " + fishQuote("("+strings.Join(calls, "; ")+")")
+	case v.Files:
+		return " -F"
+	case len(v.Choices) != 0:
+		return " -a " + fishQuote(strings.Join(v.Choices, " "))
+	default:
+		return ""
+	}
+}
+
+// fishQuote returns s single-quoted.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,101 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Zsh returns the zsh completion script of p, to be sourced or
+// installed as _<name> in a directory of $fpath.
+func Zsh(p Program) []byte {
+	fn := "_" + identifier(p.Name)
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "#compdef %s\n# zsh completion for %s, generated by %q.\n\n", p.Name, p.Name, p.Name+" completion zsh")
+
+	fmt.Fprintf(&b, "%s() {\n    local -a commands\n    commands=(\n", fn)
+	for _, cmd := range p.Commands {
+		fmt.Fprintf(&b, "        %s\n", zshQuote(strings.ReplaceAll(cmd.Name, ":", `\:`)+":"+cmd.Description))
+	}
+	b.WriteString(`    )
+
+    if (( CURRENT == 2 )); then
+        _describe -t commands 'command' commands
+        return
+    fi
+
+    shift words
+    (( CURRENT-- ))
+
+    case ${words[1]} in
+`)
+
+	for _, cmd := range p.Commands {
+		writeZshCommand(&b, cmd)
+	}
+
+	fmt.Fprintf(&b, `    esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+    %s "$@"
+else
+    compdef %s %s
+fi
+`, fn, fn, p.Name)
+
+	return b.Bytes()
+}
+
+// writeZshCommand writes the case of cmd, that completes its flags
+// and arguments with _arguments.
+func writeZshCommand(b *bytes.Buffer, cmd Command) {
+	specs := make([]string, 0, len(cmd.Flags)+2)
+	for _, f := range cmd.Flags {
+		// flags are repeatable: some of them accumulate values
+		spec := fmt.Sprintf("*-%s[%s]", f.Name, zshEscapeBrackets(f.Usage))
+		if !f.Bool {
+			spec += ":" + f.Name + ":" + zshAction(f.Value)
+		}
+		specs = append(specs, zshQuote(spec))
+	}
+	if !cmd.Arg.isZero() {
+		specs = append(specs, zshQuote("1:argument:"+zshAction(cmd.Arg)))
+	}
+	if !cmd.Rest.isZero() {
+		specs = append(specs, zshQuote("*:argument:"+zshAction(cmd.Rest)))
+	}
+
+	fmt.Fprintf(b, "    %s)\n", strings.Join(cmd.names(), "|"))
+	if len(specs) != 0 {
+		b.WriteString("        _arguments \\\n            ")
+		b.WriteString(strings.Join(specs, " \\\n            "))
+		b.WriteString("\n")
+	}
+	b.WriteString("        ;;\n")
+}
+
+// zshAction returns the _arguments action completing v.
+func zshAction(v Values) string {
+	switch {
+	case v.Files && len(v.Extensions) != 0:
+		return fmt.Sprintf(`_files -g "*.(%s)"`, strings.Join(trimExtensions(v.Extensions), "|"))
+	case v.Files:
+		return "_files"
+	case len(v.Choices) != 0:
+		return "(" + strings.Join(v.Choices, " ") + ")"
+	default:
+		return " "
+	}
+}
+
+// zshEscapeBrackets escapes the brackets of a flag description.
+func zshEscapeBrackets(s string) string {
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`).Replace(s)
+}
+
+// zshQuote returns s single-quoted.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -23,4 +23,8 @@ var (
 
 	// ErrCircularExtends signals a circular reference in the config file.
 	ErrCircularExtends = errors.New("circular reference detected")
+
+	// ErrUndefinedEnv signals a reference to an environment variable
+	// that is not set and has no default value.
+	ErrUndefinedEnv = errors.New("undefined environment variable")
 )
@@ -1,6 +1,14 @@
 package configfile
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
 
 // Find returns the first name tham matches a file path.
 // If no match is found, it returns an empty string.
@@ -12,3 +20,88 @@ func Find(names []string) string {
 	}
 	return ""
 }
+
+// FindAll returns the config files matching the glob patterns, sorted
+// and without duplicates. A pattern naming a directory matches the config
+// files it contains, non recursively.
+//
+// Files extended by another matched file are left out: they hold options
+// shared by several configs rather than benchmarks of their own.
+//
+// It returns ErrFileNotFound if a pattern matches no config file.
+func FindAll(patterns []string) ([]string, error) {
+	seen := set{}
+	filenames := []string{}
+	for _, pattern := range patterns {
+		matches, err := matchConfigFiles(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, errorutil.WithDetails(ErrFileNotFound, pattern)
+		}
+		for _, filename := range matches {
+			if seen.add(filepath.Clean(filename)) == nil {
+				filenames = append(filenames, filename)
+			}
+		}
+	}
+	sort.Strings(filenames)
+
+	parents := set{}
+	for _, filename := range filenames {
+		if parent := extendedFile(filename); parent != "" {
+			parents[parent] = true
+		}
+	}
+
+	runnable := []string{}
+	for _, filename := range filenames {
+		if !parents[filepath.Clean(filename)] {
+			runnable = append(runnable, filename)
+		}
+	}
+	return runnable, nil
+}
+
+// matchConfigFiles returns the files with a supported extension matching
+// pattern, or contained in the directory it names.
+func matchConfigFiles(pattern string) ([]string, error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errorutil.WithDetails(ErrFileNotFound, pattern, err)
+	}
+
+	filenames := []string{}
+	for _, match := range matches {
+		if _, err := newParser(extension(filepath.Ext(match))); err != nil {
+			continue
+		}
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			filenames = append(filenames, match)
+		}
+	}
+	return filenames, nil
+}
+
+// extendedFile returns the cleaned path of the file extended by the config
+// file filename, or an empty string if it extends none or cannot be read.
+func extendedFile(filename string) string {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+
+	// YAML is a superset of JSON
+	var repr struct {
+		Extends *string `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(b, &repr); err != nil || repr.Extends == nil {
+		return ""
+	}
+	return filepath.Clean(filepath.Join(filepath.Dir(filename), *repr.Extends))
+}
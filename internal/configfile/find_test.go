@@ -1,6 +1,8 @@
 package configfile_test
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/benchttp/cli/internal/configfile"
@@ -29,3 +31,60 @@ func TestFind(t *testing.T) {
 		}
 	})
 }
+
+func TestFindAll(t *testing.T) {
+	t.Run("return config files of a directory sorted", func(t *testing.T) {
+		got, err := configfile.FindAll([]string{configPath("valid")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{
+			configPath("valid/benchttp-zeros.yml"),
+			configPath("valid/benchttp.json"),
+			configPath("valid/benchttp.yaml"),
+			configPath("valid/benchttp.yml"),
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, got)
+		}
+	})
+
+	t.Run("return matches of glob patterns without duplicates", func(t *testing.T) {
+		got, err := configfile.FindAll([]string{
+			configPath("valid/*.y*ml"),
+			configPath("valid/benchttp.yml"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{
+			configPath("valid/benchttp-zeros.yml"),
+			configPath("valid/benchttp.yaml"),
+			configPath("valid/benchttp.yml"),
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, got)
+		}
+	})
+
+	t.Run("leave out files extended by other matches", func(t *testing.T) {
+		got, err := configfile.FindAll([]string{configPath("extends/extends-valid-*.yml")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{configPath("extends/extends-valid-child.yml")}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, got)
+		}
+	})
+
+	t.Run("return ErrFileNotFound for patterns without match", func(t *testing.T) {
+		_, err := configfile.FindAll([]string{configPath("valid"), configPath("nope/*.yml")})
+		if !errors.Is(err, configfile.ErrFileNotFound) {
+			t.Errorf("exp ErrFileNotFound, got %v", err)
+		}
+	})
+}
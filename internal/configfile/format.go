@@ -0,0 +1,316 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// Format returns the canonical form of the config file content in,
+// encoded in the format of extension from, in the format of extension to:
+//   - keys are ordered as in Representation, custom fields last
+//   - durations are normalized ("1000ms" becomes "1s")
+//   - YAML files are indented with 2 spaces, comments are preserved
+//
+// Comments are lost when converting to JSON. A conversion whose output
+// would not be a valid config file, such as one of a YAML file with
+// merge keys to JSON, returns ErrParse.
+func Format(in []byte, from, to string) ([]byte, error) {
+	for _, ext := range []string{from, to} {
+		if _, err := newParser(extension(ext)); err != nil {
+			return nil, errorutil.WithDetails(ErrFileExt, ext)
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(in, &doc); err != nil { // YAML is a superset of JSON
+		return nil, errorutil.WithDetails(ErrParse, err)
+	}
+	if len(doc.Content) == 0 {
+		return in, nil
+	}
+
+	root := doc.Content[0]
+	canonicalize(root, reflect.TypeOf(Representation{}))
+
+	out, err := encode(&doc, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if from != to {
+		if err := checkConverted(out, to); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// checkConverted returns a non-nil error if the content out, converted
+// to the format of extension to, is not a valid config file, so that
+// a conversion never outputs a file that cannot be parsed. Environment
+// variables are considered set, their values being irrelevant.
+func checkConverted(out []byte, to string) error {
+	parser, _ := newParser(extension(to)) // checked by Format
+	lookupEnv := func(string) (string, bool) { return "0", true }
+	if _, err := parser.Parse(out, &Representation{}, lookupEnv); err != nil {
+		return errorutil.WithDetails(ErrParse, "cannot convert to "+to, err)
+	}
+	return nil
+}
+
+// encode encodes the canonicalized document doc of extension from
+// in the format of extension to.
+func encode(doc *yaml.Node, from, to string) ([]byte, error) {
+	root := doc.Content[0]
+	if extension(to) == extJSON {
+		var buf bytes.Buffer
+		writeJSON(&buf, root, "")
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+
+	// JSON input has flow style: switch to block style
+	if extension(from) == extJSON {
+		resetStyle(root)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return separateSections(buf.Bytes()), nil
+}
+
+// separateSections inserts a blank line before each top-level key
+// of a YAML document but the first one, and before its head comment.
+func separateSections(in []byte) []byte {
+	lines := strings.SplitAfter(string(in), "\n")
+	out := make([]string, 0, len(lines))
+	blockStart := -1 // index in out of the current comment block
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			if blockStart == -1 {
+				blockStart = len(out)
+			}
+		case line != "" && line != "\n" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-"):
+			at := len(out)
+			if blockStart != -1 {
+				at = blockStart
+			}
+			if at > 0 && out[at-1] != "\n" {
+				out = append(out[:at], append([]string{"\n"}, out[at:]...)...)
+			}
+			blockStart = -1
+		default:
+			blockStart = -1
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, ""))
+}
+
+// durationKeys lists the keys of the struct types holding durations.
+var durationKeys = map[reflect.Type][]string{
+	reflect.TypeOf(RunnerRepresentation{}): {"interval", "requestTimeout", "globalTimeout"},
+	reflect.TypeOf(TestRepresentation{}):   {"target"},
+}
+
+// canonicalize sorts the keys of the mapping nodes of node according
+// to the order of the fields of typ, and normalizes durations, recursively.
+func canonicalize(node *yaml.Node, typ reflect.Type) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.SequenceNode && typ.Kind() == reflect.Slice:
+		for _, item := range node.Content {
+			canonicalize(item, typ.Elem())
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Map:
+		for i := 1; i < len(node.Content); i += 2 {
+			canonicalize(node.Content[i], typ.Elem())
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Struct:
+		fields := yamlFields(typ)
+		sortMapping(node, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if field, ok := fields[key.Value]; ok {
+				canonicalize(value, field.typ)
+			}
+			if containsString(durationKeys[typ], key.Value) {
+				normalizeDuration(value)
+			}
+		}
+	}
+}
+
+// yamlField is a field of a struct type, with its index in the struct.
+type yamlField struct {
+	index int
+	typ   reflect.Type
+}
+
+// yamlFields returns the fields of the struct type typ, by yaml key.
+func yamlFields(typ reflect.Type) map[string]yamlField {
+	fields := map[string]yamlField{}
+	for i := 0; i < typ.NumField(); i++ {
+		key := strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0]
+		fields[key] = yamlField{index: i, typ: typ.Field(i).Type}
+	}
+	return fields
+}
+
+// sortMapping sorts the key-value pairs of a mapping node by the index
+// of their field, unknown keys last in their original order.
+func sortMapping(node *yaml.Node, fields map[string]yamlField) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+
+	rank := func(p pair) int {
+		if field, ok := fields[p.key.Value]; ok {
+			return field.index
+		}
+		return len(fields)
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return rank(pairs[i]) < rank(pairs[j])
+	})
+
+	for i, p := range pairs {
+		node.Content[2*i], node.Content[2*i+1] = p.key, p.value
+	}
+}
+
+// integerRgx matches integer values, that are not durations.
+var integerRgx = regexp.MustCompile(`^-?[0-9]+$`)
+
+// normalizeDuration rewrites the duration held by a scalar node
+// in its canonical form.
+func normalizeDuration(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || integerRgx.MatchString(node.Value) {
+		return
+	}
+	d, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return
+	}
+	node.Value = FormatDuration(d)
+}
+
+// FormatDuration returns the shortest representation of d:
+// unlike time.Duration.String, zero minutes and seconds are omitted
+// ("1m" instead of "1m0s").
+func FormatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// resetStyle sets the style of node and its children to the default
+// block style. Strings that need quotes are still quoted by the encoder.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// writeJSON writes node as indented JSON in buf, keeping the order
+// of the keys.
+func writeJSON(buf *bytes.Buffer, node *yaml.Node, indent string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		writeJSON(buf, node.Content[0], indent)
+	case yaml.AliasNode:
+		writeJSON(buf, node.Alias, indent)
+	case yaml.MappingNode:
+		writeJSONList(buf, "{", "}", len(node.Content)/2, indent, func(i int, inner string) {
+			writeJSONString(buf, node.Content[2*i].Value)
+			buf.WriteString(": ")
+			writeJSON(buf, node.Content[2*i+1], inner)
+		})
+	case yaml.SequenceNode:
+		writeJSONList(buf, "[", "]", len(node.Content), indent, func(i int, inner string) {
+			writeJSON(buf, node.Content[i], inner)
+		})
+	case yaml.ScalarNode:
+		writeJSONScalar(buf, node)
+	}
+}
+
+// writeJSONList writes n items in buf between the delimiters start
+// and end, one item per line.
+func writeJSONList(buf *bytes.Buffer, start, end string, n int, indent string, writeItem func(i int, inner string)) {
+	if n == 0 {
+		buf.WriteString(start + end)
+		return
+	}
+	inner := indent + "  "
+	buf.WriteString(start + "\n")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(inner)
+		writeItem(i, inner)
+	}
+	buf.WriteString("\n" + indent + end)
+}
+
+// writeJSONScalar writes the value of a scalar node in buf, as a JSON
+// literal for numbers, booleans and null, else as a string.
+func writeJSONScalar(buf *bytes.Buffer, node *yaml.Node) {
+	switch node.Tag {
+	case "!!int", "!!float", "!!bool":
+		var v interface{}
+		if err := node.Decode(&v); err == nil {
+			b, _ := json.Marshal(v)
+			buf.Write(b)
+			return
+		}
+	case "!!null":
+		buf.WriteString("null")
+		return
+	}
+	writeJSONString(buf, node.Value)
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)           //nolint:errcheck // strings are always encodable
+	buf.Truncate(buf.Len() - 1) // remove trailing newline
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
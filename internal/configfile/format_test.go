@@ -0,0 +1,193 @@
+package configfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benchttp/cli/internal/configfile"
+)
+
+func TestFormat(t *testing.T) {
+	const messy = `# benchmark of the users api
+
+# run on each release
+tests:
+    - target: 1000ms # one second
+      predicate: LT
+      name: mean
+      field: ResponseTimes.Mean
+    - {name: no failure, field: RequestFailureCount, predicate: EQ, target: 0}
+runner:
+    globalTimeout: 60s
+    x-note: custom
+    concurrency: 2
+    interval: 0.5s
+request:
+    url: https://example.com/users # api
+    header:
+        X-B: [b]
+        X-A: [a]
+    method: GET
+`
+
+	t.Run("canonicalize yaml", func(t *testing.T) {
+		const exp = `# benchmark of the users api
+
+request:
+  method: GET
+  url: https://example.com/users # api
+  header:
+    X-B: [b]
+    X-A: [a]
+
+runner:
+  concurrency: 2
+  interval: 500ms
+  globalTimeout: 1m
+  x-note: custom
+
+# run on each release
+tests:
+  - name: mean
+    field: ResponseTimes.Mean
+    predicate: LT
+    target: 1s # one second
+  - {name: no failure, field: RequestFailureCount, predicate: EQ, target: 0}
+`
+		got, err := configfile.Format([]byte(messy), ".yml", ".yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != exp {
+			t.Errorf("bad format:\nexp:\n%s\ngot:\n%s", exp, got)
+		}
+
+		again, err := configfile.Format(got, ".yml", ".yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(got) {
+			t.Errorf("format is not idempotent:\n%s", again)
+		}
+	})
+
+	t.Run("convert yaml to json and back", func(t *testing.T) {
+		const expJSON = `{
+  "request": {
+    "method": "GET",
+    "url": "https://example.com/users",
+    "header": {
+      "X-B": [
+        "b"
+      ],
+      "X-A": [
+        "a"
+      ]
+    }
+  },
+  "runner": {
+    "concurrency": 2,
+    "interval": "500ms",
+    "globalTimeout": "1m",
+    "x-note": "custom"
+  },
+  "tests": [
+    {
+      "name": "mean",
+      "field": "ResponseTimes.Mean",
+      "predicate": "LT",
+      "target": "1s"
+    },
+    {
+      "name": "no failure",
+      "field": "RequestFailureCount",
+      "predicate": "EQ",
+      "target": 0
+    }
+  ]
+}
+`
+		gotJSON, err := configfile.Format([]byte(messy), ".yml", ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(gotJSON) != expJSON {
+			t.Errorf("bad json:\nexp:\n%s\ngot:\n%s", expJSON, gotJSON)
+		}
+
+		gotYAML, err := configfile.Format(gotJSON, ".json", ".yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		const expYAMLPrefix = "request:\n  method: GET\n  url: https://example.com/users\n  header:\n    X-B:\n      - b\n"
+		if len(gotYAML) < len(expYAMLPrefix) || string(gotYAML[:len(expYAMLPrefix)]) != expYAMLPrefix {
+			t.Errorf("bad yaml:\n%s", gotYAML)
+		}
+	})
+
+	t.Run("convert references to environment variables", func(t *testing.T) {
+		in := "runner:\n  requests: ${BENCHTTP_TEST_REQUESTS:-10}\n  interval: ${BENCHTTP_TEST_INTERVAL:-1s}\n"
+		out, err := configfile.Format([]byte(in), ".yml", ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		filename := filepath.Join(t.TempDir(), "benchttp.json")
+		if err := os.WriteFile(filename, out, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := configfile.Parse(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Runner.Requests != 10 || cfg.Runner.Interval != time.Second {
+			t.Errorf("unexpected parsed runner: %+v", cfg.Runner)
+		}
+	})
+
+	t.Run("refuse conversions to invalid files", func(t *testing.T) {
+		in := "x-base: &base\n  concurrency: 2\nrunner:\n  <<: *base\n  requests: 10\n"
+		if _, err := configfile.Format([]byte(in), ".yml", ".yml"); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := configfile.Format([]byte(in), ".yml", ".json")
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+
+	t.Run("return ErrFileExt", func(t *testing.T) {
+		_, err := configfile.Format([]byte("{}"), ".yml", ".toml")
+		if !errors.Is(err, configfile.ErrFileExt) {
+			t.Errorf("exp ErrFileExt, got %v", err)
+		}
+	})
+
+	t.Run("return ErrParse", func(t *testing.T) {
+		_, err := configfile.Format([]byte("request: [\n"), ".yml", ".yml")
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+}
+
+func TestFormatDuration(t *testing.T) {
+	for in, exp := range map[time.Duration]string{
+		0:                            "0s",
+		1500 * time.Millisecond:      "1.5s",
+		time.Minute:                  "1m",
+		90 * time.Second:             "1m30s",
+		time.Hour:                    "1h",
+		time.Hour + time.Second:      "1h0m1s",
+		2*time.Hour + 30*time.Minute: "2h30m",
+		250 * time.Microsecond:       "250µs",
+	} {
+		if got := configfile.FormatDuration(in); got != exp {
+			t.Errorf("%d: exp %s, got %s", in, exp, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package configfile
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envRefRgx matches references to environment variables in a config file:
+// ${NAME} or ${NAME:-default}. A reference prefixed by an extra "$"
+// is escaped.
+var envRefRgx = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolate replaces references to environment variables in b
+// with their values, looked up with lookupEnv. "$${NAME}" is unescaped
+// as "${NAME}".
+// It returns the names of the referenced variables that are not set
+// and have no default value, in which case they are left as is.
+func interpolate(b []byte, lookupEnv func(string) (string, bool)) (out []byte, undefined []string) {
+	out = envRefRgx.ReplaceAllFunc(b, func(ref []byte) []byte {
+		if strings.HasPrefix(string(ref), "$$") {
+			return ref[1:]
+		}
+
+		matches := envRefRgx.FindSubmatch(ref)
+		name, def := string(matches[1]), matches[2]
+
+		if val, ok := lookupEnv(name); ok {
+			return []byte(val)
+		}
+		if def != nil {
+			return def
+		}
+		undefined = append(undefined, name)
+		return ref
+	})
+
+	return out, undefined
+}
+
+// interpolateNode interpolates the scalar values of node and its
+// descendants, see interpolate. The keys of the mappings are left as is.
+// The plain scalars that are modified are resolved again, so that
+// "${REQUESTS:-100}" is decoded as an integer.
+func interpolateNode(node *yaml.Node, lookupEnv func(string) (string, bool)) (undefined []string) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		out, undefined := interpolate([]byte(node.Value), lookupEnv)
+		if string(out) != node.Value {
+			node.Value = string(out)
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+		}
+		return undefined
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			undefined = append(undefined, interpolateNode(node.Content[i], lookupEnv)...)
+		}
+	default:
+		for _, child := range node.Content {
+			undefined = append(undefined, interpolateNode(child, lookupEnv)...)
+		}
+	}
+	return undefined
+}
+
+// interpolateJSON interpolates the string values of the decoded JSON
+// document v and its descendants, see interpolate, typ being the type
+// it is decoded into. The keys of the objects are left as is. A string
+// made of a single reference is decoded as a number if typ is numeric,
+// so that "${REQUESTS:-100}" is decoded as an integer. The names of the
+// undefined variables are sorted, as the objects are unordered.
+func interpolateJSON(
+	v interface{},
+	typ reflect.Type,
+	lookupEnv func(string) (string, bool),
+) (out interface{}, undefined []string) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch v := v.(type) {
+	case string:
+		out, undefined := interpolate([]byte(v), lookupEnv)
+		if isNumber(out, typ) && isSingleEnvRef(v) {
+			return json.Number(out), undefined
+		}
+		return string(out), undefined
+	case map[string]interface{}:
+		for key, child := range v {
+			var childUndefined []string
+			v[key], childUndefined = interpolateJSON(child, childType(typ, key), lookupEnv)
+			undefined = append(undefined, childUndefined...)
+		}
+	case []interface{}:
+		for i, child := range v {
+			var childUndefined []string
+			v[i], childUndefined = interpolateJSON(child, childType(typ, ""), lookupEnv)
+			undefined = append(undefined, childUndefined...)
+		}
+	}
+	sort.Strings(undefined)
+	return v, undefined
+}
+
+// childType returns the type of the value of key in a value of type typ,
+// or of its elements if key is empty. It returns nil if it is unknown.
+func childType(typ reflect.Type, key string) reflect.Type {
+	switch {
+	case typ == nil:
+		return nil
+	case typ.Kind() == reflect.Struct && key != "":
+		return yamlFields(typ)[key].typ
+	case typ.Kind() == reflect.Map, typ.Kind() == reflect.Slice:
+		return typ.Elem()
+	}
+	return nil
+}
+
+// isSingleEnvRef returns true if s is made of a single unescaped
+// reference to an environment variable.
+func isSingleEnvRef(s string) bool {
+	loc := envRefRgx.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && loc[1] == len(s) && !strings.HasPrefix(s, "$$")
+}
+
+// isNumber returns true if b is a valid value of the numeric type typ.
+func isNumber(b []byte, typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err := strconv.ParseInt(string(b), 10, typ.Bits())
+		return err == nil
+	case reflect.Float32, reflect.Float64:
+		_, err := strconv.ParseFloat(string(b), typ.Bits())
+		return err == nil
+	}
+	return false
+}
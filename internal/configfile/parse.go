@@ -1,25 +1,305 @@
 package configfile
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/benchttp/engine/configparse"
 	"github.com/benchttp/engine/runner"
 
+	"github.com/benchttp/cli/internal/auth"
+	"github.com/benchttp/cli/internal/baseline"
 	"github.com/benchttp/cli/internal/errorutil"
+	"github.com/benchttp/cli/internal/redact"
+	"github.com/benchttp/cli/internal/testsuite"
 )
 
 // Parse parses a benchttp runner config file into a runner.ConfigGlobal
 // and returns it or the first non-nil error occurring in the process,
 // which can be any of the values declared in the package.
 func Parse(filename string) (cfg runner.Config, err error) {
-	uconfs, err := parseFileRecursive(filename, []configparse.Representation{}, set{})
+	cfg, _, err = ParseScenarios(filename)
+	return
+}
+
+// Scenario is a variant of the config of a file, declared in its list
+// "scenarios".
+type Scenario struct {
+	// Name identifies the scenario in the config file.
+	Name string
+
+	// Config is the top-level config of the file overridden
+	// by the options of the scenario.
+	Config runner.Config
+
+	// RelativeTests are the tests of the scenario with a relative
+	// target, or the top-level ones if it declares no tests.
+	RelativeTests []testsuite.RelativeCase
+
+	// Warnings are the names of the tests of the scenario of severity
+	// warn, or of the top-level ones if it declares no tests.
+	Warnings testsuite.Warnings
+}
+
+// ParseScenarios parses a config file like Parse, and also returns
+// the scenarios it declares, if any. If the file declares none, the ones
+// of its closest parent are returned.
+func ParseScenarios(filename string) (cfg runner.Config, scenarios []Scenario, err error) {
+	file, err := ParseFile(filename)
+	return file.Config, file.Scenarios, err
+}
+
+// File is a parsed config file, with the options that are specific
+// to the CLI.
+type File struct {
+	// Config is the config of the file, merged with the ones
+	// of its parents.
+	Config runner.Config
+
+	// Scenarios are the scenarios declared by the file, or by its
+	// closest parent if it declares none.
+	Scenarios []Scenario
+
+	// Tolerances are the regressions allowed for the metrics compared
+	// with a baseline, merged with the ones of its parents.
+	Tolerances baseline.Tolerances
+
+	// RelativeTests are the tests with a target relative to another
+	// metric, that are run by the CLI. Like the other tests, they are
+	// the ones of the file, or of its closest parent declaring tests.
+	RelativeTests []testsuite.RelativeCase
+
+	// Warnings are the names of the tests of severity warn, whose
+	// failures do not fail the run, among the tests of the file.
+	Warnings testsuite.Warnings
+
+	// Secrets are the credentials resolved from the request.auth key
+	// of the file and of its parents, to be redacted from the output.
+	Secrets []string
+
+	// Redact are the locations of sensitive values of the file,
+	// merged with the ones of its parents.
+	Redact redact.Rules
+}
+
+// ParseFile parses a config file like ParseScenarios, and also returns
+// the options of the file that are specific to the CLI.
+func ParseFile(filename string) (File, error) {
+	return ParseFileWithEnv(filename, os.LookupEnv)
+}
+
+// ParseFileWithEnv parses a config file like ParseFile, looking up
+// the environment variables it references with lookupEnv, e.g. to
+// complete the process environment with the ones of an env file.
+func ParseFileWithEnv(filename string, lookupEnv func(name string) (string, bool)) (file File, err error) {
+	reprs, err := parseFileRecursive(filename, []Representation{}, set{}, lookupEnv, &file.Secrets)
 	if err != nil {
 		return
 	}
-	return parseAndMergeConfigs(uconfs)
+	if file.Config, err = parseAndMergeConfigs(reprs); err != nil {
+		return
+	}
+	if file.Tolerances, err = parseTolerances(reprs); err != nil {
+		return
+	}
+	if file.Redact, err = parseRedactRules(reprs); err != nil {
+		return
+	}
+	for _, repr := range reprs {
+		if len(repr.Tests) != 0 {
+			file.RelativeTests, err = parseRelativeTests(repr.Tests, "tests", &file.Config)
+			if err != nil {
+				return
+			}
+			file.Warnings, err = parseWarnings(repr.Tests, "tests")
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+	for _, repr := range reprs {
+		if len(repr.Scenarios) != 0 {
+			file.Scenarios, err = parseScenarios(repr.Scenarios, file)
+			return
+		}
+	}
+	return
+}
+
+// parseTolerances parses the baseline tolerances of reprs, the ones
+// of a file overriding the ones of its parents.
+func parseTolerances(reprs []Representation) (baseline.Tolerances, error) {
+	tolerances := baseline.Tolerances{}
+	for i := len(reprs) - 1; i >= 0; i-- {
+		if reprs[i].Baseline == nil {
+			continue
+		}
+		for field, v := range reprs[i].Baseline.Tolerances {
+			t, err := baseline.ParseTolerance(runner.MetricsField(field), v)
+			if err != nil {
+				return nil, errorutil.WithDetails(ErrParse, "baseline.tolerances", err)
+			}
+			tolerances[runner.MetricsField(field)] = t
+		}
+	}
+	return tolerances, nil
+}
+
+// parseRedactRules merges the redaction rules of reprs, the ones
+// of a file adding up to the ones of its parents.
+func parseRedactRules(reprs []Representation) (redact.Rules, error) {
+	rules := redact.Rules{}
+	for i := len(reprs) - 1; i >= 0; i-- {
+		r := reprs[i].Redact
+		if r == nil {
+			continue
+		}
+		for _, kind := range []struct {
+			name     string
+			patterns []string
+			rules    *[]string
+		}{
+			{"header", r.Header, &rules.Headers},
+			{"query", r.Query, &rules.Query},
+			{"body", r.Body, &rules.Body},
+		} {
+			for j, pattern := range kind.patterns {
+				if pattern == "" {
+					return redact.Rules{}, errorutil.WithDetails(ErrParse, fmt.Sprintf("redact.%s[%d]: empty pattern", kind.name, j))
+				}
+			}
+			*kind.rules = append(*kind.rules, kind.patterns...)
+		}
+	}
+	return rules, nil
+}
+
+// parseScenarios parses the scenarios reprs, overriding the config,
+// relative tests and warnings of base with the options of each of them.
+func parseScenarios(reprs []ScenarioRepresentation, base File) ([]Scenario, error) {
+	scenarios := make([]Scenario, len(reprs))
+	names := set{}
+	for i, repr := range reprs {
+		if repr.Name == nil || *repr.Name == "" {
+			return nil, errorutil.WithDetails(ErrParse, fmt.Sprintf("scenarios[%d].name: missing", i))
+		}
+		if err := names.add(*repr.Name); err != nil {
+			return nil, errorutil.WithDetails(ErrParse, fmt.Sprintf("scenarios[%d].name: duplicate name %q", i, *repr.Name))
+		}
+
+		scenarioRepr := Representation{Request: repr.Request, Runner: repr.Runner, Tests: repr.Tests}
+		scenarioConfig, err := configparse.ParseRepresentation(scenarioRepr.engineRepresentation())
+		if err != nil {
+			return nil, errorutil.WithDetails(ErrParse, fmt.Sprintf("scenarios[%d]", i), err)
+		}
+
+		// Override merges the header into the one of its base:
+		// give each scenario its own copy
+		scenarioBase := base.Config
+		scenarioBase.Request.Header = base.Config.Request.Header.Clone()
+
+		scenarios[i] = Scenario{
+			Name:          *repr.Name,
+			Config:        scenarioConfig.Override(scenarioBase),
+			RelativeTests: base.RelativeTests,
+			Warnings:      base.Warnings,
+		}
+		if len(repr.Tests) != 0 {
+			path := fmt.Sprintf("scenarios[%d].tests", i)
+			scenarios[i].RelativeTests, err = parseRelativeTests(repr.Tests, path, &scenarios[i].Config)
+			if err != nil {
+				return nil, err
+			}
+			scenarios[i].Warnings, err = parseWarnings(repr.Tests, path)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return scenarios, nil
+}
+
+// parseRelativeTests parses the tests of reprs with a relative target,
+// path locating them in the file. As they replace the inherited tests,
+// the ones of cfg are cleared if reprs has only relative tests.
+func parseRelativeTests(reprs []TestRepresentation, path string, cfg *runner.Config) ([]testsuite.RelativeCase, error) {
+	cases := []testsuite.RelativeCase{}
+	for i, repr := range reprs {
+		if !repr.isRelative() {
+			continue
+		}
+		c, err := parseRelativeTest(repr)
+		if err != nil {
+			return nil, errorutil.WithDetails(ErrParse, fmt.Sprintf("%s[%d]", path, i), err)
+		}
+		cases = append(cases, c)
+	}
+
+	if len(cases) == len(reprs) {
+		cfg.Tests = nil
+	}
+	return cases, nil
+}
+
+// parseWarnings returns the names of the tests of reprs of severity
+// warn, path locating them in the file.
+func parseWarnings(reprs []TestRepresentation, path string) (testsuite.Warnings, error) {
+	warnings := testsuite.Warnings{}
+	for i, repr := range reprs {
+		if repr.Severity == nil {
+			continue
+		}
+		severity, err := testsuite.ParseSeverity(*repr.Severity)
+		if err != nil {
+			return nil, errorutil.WithDetails(ErrParse, fmt.Sprintf("%s[%d].severity", path, i), err)
+		}
+		if severity == testsuite.SeverityWarn && repr.Name != nil {
+			warnings = append(warnings, *repr.Name)
+		}
+	}
+	return warnings, nil
+}
+
+// parseRelativeTest parses a test with a relative target.
+func parseRelativeTest(repr TestRepresentation) (testsuite.RelativeCase, error) {
+	if repr.Name == nil || repr.Field == nil || repr.Predicate == nil {
+		return testsuite.RelativeCase{}, errors.New("missing name, field or predicate")
+	}
+
+	// decode the generic target into its representation,
+	// rejecting unknown keys
+	b, err := json.Marshal(repr.Target)
+	if err != nil {
+		return testsuite.RelativeCase{}, err
+	}
+	var target RelativeTargetRepresentation
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&target); err != nil {
+		return testsuite.RelativeCase{}, fmt.Errorf("target: %w", err)
+	}
+	if target.RelativeTo == nil {
+		return testsuite.RelativeCase{}, errors.New("target.relativeTo: missing field")
+	}
+
+	c := testsuite.RelativeCase{
+		Name:       *repr.Name,
+		Field:      runner.MetricsField(*repr.Field),
+		Predicate:  runner.TestPredicate(*repr.Predicate),
+		RelativeTo: *target.RelativeTo,
+		Factor:     1,
+	}
+	if target.Factor != nil {
+		c.Factor = *target.Factor
+	}
+	return c, c.Validate()
 }
 
 // set is a collection of unique string values.
@@ -38,23 +318,27 @@ func (s set) add(v string) error {
 // parseFileRecursive parses a config file and its parent found from key
 // "extends" recursively until the root config file is reached.
 // It returns the list of all parsed configs or the first non-nil error
-// occurring in the process.
+// occurring in the process. The resolved credentials are appended
+// to secrets.
 func parseFileRecursive(
 	filename string,
-	reprs []configparse.Representation,
+	reprs []Representation,
 	seen set,
-) ([]configparse.Representation, error) {
+	lookupEnv auth.LookupEnv,
+	secrets *[]string,
+) ([]Representation, error) {
 	// avoid infinite recursion caused by circular reference
 	if err := seen.add(filename); err != nil {
 		return reprs, ErrCircularExtends
 	}
 
 	// parse current file, append parsed config
-	repr, err := parseFile(filename)
+	repr, fileSecrets, err := parseFile(filename, lookupEnv)
 	if err != nil {
 		return reprs, err
 	}
 	reprs = append(reprs, repr)
+	*secrets = append(*secrets, fileSecrets...)
 
 	// root config reached: stop now and return the parsed configs
 	if repr.Extends == nil {
@@ -63,39 +347,57 @@ func parseFileRecursive(
 
 	// config has parent: resolve its path and parse it recursively
 	parentPath := filepath.Join(filepath.Dir(filename), *repr.Extends)
-	return parseFileRecursive(parentPath, reprs, seen)
+	return parseFileRecursive(parentPath, reprs, seen, lookupEnv, secrets)
 }
 
-// parseFile parses a single config file and returns the result as an
-// configparse.Representation and an appropriate error predeclared in the package.
-func parseFile(filename string) (repr configparse.Representation, err error) {
+// parseFile parses a single config file and returns the result as a
+// Representation and an appropriate error predeclared in the package,
+// along with the credentials resolved from its auth keys.
+// The environment variables it references are looked up with lookupEnv.
+func parseFile(filename string, lookupEnv auth.LookupEnv) (repr Representation, secrets []string, err error) {
 	b, err := os.ReadFile(filename)
 	switch {
 	case err == nil:
 	case errors.Is(err, os.ErrNotExist):
-		return repr, errorutil.WithDetails(ErrFileNotFound, filename)
+		return repr, nil, errorutil.WithDetails(ErrFileNotFound, filename)
 	default:
-		return repr, errorutil.WithDetails(ErrFileRead, filename, err)
+		return repr, nil, errorutil.WithDetails(ErrFileRead, filename, err)
 	}
 
 	ext := extension(filepath.Ext(filename))
 	parser, err := newParser(ext)
 	if err != nil {
-		return repr, errorutil.WithDetails(ErrFileExt, ext, err)
+		return repr, nil, errorutil.WithDetails(ErrFileExt, ext, err)
 	}
 
-	if err = parser.Parse(b, &repr); err != nil {
-		return repr, errorutil.WithDetails(ErrParse, filename, err)
+	undefined, err := parser.Parse(b, &repr, lookupEnv)
+	switch {
+	case err != nil:
+		return repr, nil, errorutil.WithDetails(ErrParse, filename, err)
+	case len(undefined) != 0:
+		return repr, nil, errorutil.WithDetails(ErrUndefinedEnv, filename, strings.Join(undefined, ", "))
 	}
 
-	return repr, nil
+	secrets, err = resolveAuth(&repr.Request, filepath.Dir(filename), lookupEnv)
+	if err != nil {
+		return repr, nil, errorutil.WithDetails(ErrParse, filename, err)
+	}
+	for i := range repr.Scenarios {
+		scenarioSecrets, err := resolveAuth(&repr.Scenarios[i].Request, filepath.Dir(filename), lookupEnv)
+		if err != nil {
+			return repr, nil, errorutil.WithDetails(ErrParse, filename, fmt.Errorf("scenarios[%d].%w", i, err))
+		}
+		secrets = append(secrets, scenarioSecrets...)
+	}
+
+	return repr, secrets, nil
 }
 
 // parseAndMergeConfigs iterates backwards over uconfs, parsing them
 // as runner.ConfigGlobal and merging them into a single one.
 // It returns the merged result or the first non-nil error occurring in the
 // process.
-func parseAndMergeConfigs(reprs []configparse.Representation) (cfg runner.Config, err error) {
+func parseAndMergeConfigs(reprs []Representation) (cfg runner.Config, err error) {
 	if len(reprs) == 0 { // supposedly catched upstream, should not occur
 		return cfg, errors.New(
 			"an unacceptable error occurred parsing the config file, " +
@@ -105,10 +407,13 @@ func parseAndMergeConfigs(reprs []configparse.Representation) (cfg runner.Config
 	}
 
 	cfg = runner.DefaultConfig()
+	// the default header is shared by all default configs: give cfg its own,
+	// as Override adds the header of each file to it
+	cfg.Request.Header = http.Header{}
 
 	for i := len(reprs) - 1; i >= 0; i-- {
 		repr := reprs[i]
-		currentConfig, err := configparse.ParseRepresentation(repr)
+		currentConfig, err := configparse.ParseRepresentation(repr.engineRepresentation())
 		if err != nil {
 			return cfg, errorutil.WithDetails(ErrParse, err)
 		}
@@ -117,3 +422,33 @@ func parseAndMergeConfigs(reprs []configparse.Representation) (cfg runner.Config
 
 	return cfg, nil
 }
+
+// resolveAuth resolves the credentials of req.Auth, if any, into
+// the Authorization header of req, and returns the resolved secrets.
+// Relative file paths are resolved from dir, environment variables
+// with lookupEnv.
+func resolveAuth(req *RequestRepresentation, dir string, lookupEnv auth.LookupEnv) ([]string, error) {
+	a := req.Auth
+	if a == nil {
+		return nil, nil
+	}
+
+	value, secrets, err := auth.Auth{
+		Type:     auth.Type(a.Type),
+		Username: a.Username,
+		Password: auth.Source(a.Password),
+		Token:    auth.Source(a.Token),
+	}.Header(dir, lookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("request.auth: %w", err)
+	}
+
+	header := map[string][]string{}
+	for key, values := range req.Header {
+		header[key] = values
+	}
+	header[auth.HeaderKey] = []string{value}
+	req.Header = header
+
+	return secrets, nil
+}
@@ -12,7 +12,10 @@ import (
 
 	"github.com/benchttp/engine/runner"
 
+	"github.com/benchttp/cli/internal/baseline"
 	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/redact"
+	"github.com/benchttp/cli/internal/testsuite"
 )
 
 const (
@@ -64,6 +67,16 @@ func TestParse(t *testing.T) {
 				path:   configPath("extends/extends-circular-0.yml"),
 				expErr: configfile.ErrCircularExtends,
 			},
+			{
+				label:  "invalid auth",
+				path:   configPath("auth/invalid-type.yml"),
+				expErr: configfile.ErrParse,
+			},
+			{
+				label:  "undefined environment variable",
+				path:   configPath("env/undefined.yml"),
+				expErr: configfile.ErrUndefinedEnv,
+			},
 		}
 
 		for _, tc := range testcases {
@@ -181,6 +194,301 @@ func TestParse(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("interpolate environment variables", func(t *testing.T) {
+		t.Setenv("BENCHTTP_TEST_URL", "http://env.config")
+		t.Setenv("BENCHTTP_TEST_TOKEN", "abc")
+
+		for _, ext := range []string{".yml", ".json"} {
+			cfg, err := configfile.Parse(configPath("env/interpolate" + ext))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := "http://env.config", cfg.Request.URL.String(); got != exp {
+				t.Errorf("%s: url: exp %s, got %s", ext, exp, got)
+			}
+
+			for key, exp := range map[string]string{
+				"Authorization": "Bearer abc",
+				"X-Escaped":     "${NOT_INTERPOLATED}",
+			} {
+				if got := cfg.Request.Header.Get(key); got != exp {
+					t.Errorf("%s: header %s: exp %q, got %q", ext, key, exp, got)
+				}
+			}
+
+			if exp, got := 42, cfg.Runner.Requests; got != exp {
+				t.Errorf("%s: requests: exp %d, got %d", ext, exp, got)
+			}
+			if exp, got := 3, cfg.Runner.Concurrency; got != exp {
+				t.Errorf("%s: concurrency: exp %d, got %d", ext, exp, got)
+			}
+			if exp, got := 2*time.Second, cfg.Runner.GlobalTimeout; got != exp {
+				t.Errorf("%s: globalTimeout: exp %s, got %s", ext, exp, got)
+			}
+		}
+	})
+
+	t.Run("interpolate json values as strings", func(t *testing.T) {
+		token := `a", "key0": ["b\`
+		t.Setenv("BENCHTTP_TEST_TOKEN", token)
+
+		cfg, err := configfile.Parse(configPath("env/escape.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, got := "Bearer "+token, cfg.Request.Header.Get("Authorization"); got != exp {
+			t.Errorf("header Authorization: exp %q, got %q", exp, got)
+		}
+		if got := cfg.Request.Header["key0"]; got != nil {
+			t.Errorf("unexpected header key0: %v", got)
+		}
+	})
+
+	t.Run("ignore environment variables in comments", func(t *testing.T) {
+		cfg, err := configfile.Parse(configPath("env/comment.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, got := "http://localhost:9999", cfg.Request.URL.String(); got != exp {
+			t.Errorf("url: exp %s, got %s", exp, got)
+		}
+	})
+
+	t.Run("resolve auth into header", func(t *testing.T) {
+		cfg, err := configfile.Parse(configPath("auth/bearer-file.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for key, exp := range map[string]string{
+			"Authorization": "Bearer s3cr3t",
+			"key0":          "val0",
+		} {
+			if got := cfg.Request.Header[key]; len(got) != 1 || got[0] != exp {
+				t.Errorf("header %s: exp [%s], got %v", key, exp, got)
+			}
+		}
+	})
+
+	t.Run("parse example config files", func(t *testing.T) {
+		// default.yml lists the default values, with an empty url
+		for _, name := range []string{"full.yml", "scenarios.yml", "test-suite.yml"} {
+			filename := filepath.Join("../../examples/config", name)
+			if _, err := configfile.ParseFile(filename); err != nil {
+				t.Errorf("%s: %v", filename, err)
+			}
+		}
+	})
+
+	t.Run("return resolved auth secrets", func(t *testing.T) {
+		file, err := configfile.ParseFile(configPath("auth/bearer-file.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(file.Secrets) != 1 || file.Secrets[0] != "s3cr3t" {
+			t.Errorf("exp secrets [s3cr3t], got %v", file.Secrets)
+		}
+	})
+}
+
+func TestParseScenarios(t *testing.T) {
+	t.Run("override top-level config with scenarios", func(t *testing.T) {
+		cfg, scenarios, err := configfile.ParseScenarios(configPath("scenarios/journey.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scenarios) != 2 {
+			t.Fatalf("exp 2 scenarios, got %d", len(scenarios))
+		}
+
+		list, create := scenarios[0], scenarios[1]
+		if list.Name != "list" || create.Name != "create" {
+			t.Errorf("exp names list, create, got %s, %s", list.Name, create.Name)
+		}
+
+		// inherited values
+		if list.Config.Request.Method != "GET" || list.Config.Runner.Concurrency != 2 {
+			t.Errorf("list: exp inherited method and concurrency, got %s, %d",
+				list.Config.Request.Method, list.Config.Runner.Concurrency)
+		}
+		if len(list.Config.Tests) != 1 || list.Config.Tests[0].Name != "no failure" {
+			t.Errorf("list: exp inherited tests, got %v", list.Config.Tests)
+		}
+
+		// overridden values
+		if create.Config.Request.Method != "POST" || create.Config.Runner.Concurrency != 1 {
+			t.Errorf("create: exp overridden method and concurrency, got %s, %d",
+				create.Config.Request.Method, create.Config.Runner.Concurrency)
+		}
+		if create.Config.Runner.Requests != 10 {
+			t.Errorf("create: exp inherited requests, got %d", create.Config.Runner.Requests)
+		}
+		if len(create.Config.Tests) != 1 || create.Config.Tests[0].Name != "fast" {
+			t.Errorf("create: exp overridden tests, got %v", create.Config.Tests)
+		}
+		if got := create.Config.Request.Header; len(got) != 2 || got["key0"][0] != "val0" || got["key1"][0] != "val1" {
+			t.Errorf("create: exp merged header, got %v", got)
+		}
+
+		// headers of a scenario do not leak into the others
+		if _, ok := list.Config.Request.Header["key1"]; ok {
+			t.Errorf("exp header of scenario create to be its own, got %v", list.Config.Request.Header)
+		}
+		if _, ok := cfg.Request.Header["key1"]; ok {
+			t.Errorf("exp header of scenario create to be its own, got %v", cfg.Request.Header)
+		}
+	})
+
+	t.Run("return nil scenarios if none declared", func(t *testing.T) {
+		_, scenarios, err := configfile.ParseScenarios(goodFileYML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scenarios != nil {
+			t.Errorf("exp nil scenarios, got %v", scenarios)
+		}
+	})
+
+	t.Run("return ErrParse for duplicate names", func(t *testing.T) {
+		_, _, err := configfile.ParseScenarios(configPath("scenarios/duplicate.yml"))
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	t.Run("merge baseline tolerances with the ones of parents", func(t *testing.T) {
+		file, err := configfile.ParseFile(configPath("baseline/child.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := baseline.Tolerances{
+			"ResponseTimes.Mean":  {Absolute: 20 * time.Millisecond},
+			"ResponseTimes.Max":   {Relative: 0.25},
+			"RequestFailureCount": {Absolute: 0},
+		}
+		if !reflect.DeepEqual(file.Tolerances, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, file.Tolerances)
+		}
+		if u := file.Config.Request.URL.String(); u != "http://localhost:9999/items" {
+			t.Errorf("exp inherited url, got %s", u)
+		}
+	})
+
+	t.Run("return ErrParse for invalid tolerances", func(t *testing.T) {
+		_, err := configfile.ParseFile(configPath("baseline/invalid.yml"))
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+
+	t.Run("merge redaction rules with the ones of parents", func(t *testing.T) {
+		file, err := configfile.ParseFile(configPath("redact/child.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := redact.Rules{
+			Headers: []string{"X-Secret-*", "X-Token"},
+			Query:   []string{"*token"},
+			Body:    []string{"user.password"},
+		}
+		if !reflect.DeepEqual(file.Redact, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, file.Redact)
+		}
+	})
+
+	t.Run("return ErrParse for empty redaction patterns", func(t *testing.T) {
+		_, err := configfile.ParseFile(configPath("redact/invalid.yml"))
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+}
+
+func TestParseFile_relativeTests(t *testing.T) {
+	t.Run("split relative tests from the engine ones", func(t *testing.T) {
+		file, err := configfile.ParseFile(configPath("relative/tests.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mean := testsuite.RelativeCase{
+			Name:       "mean within 10% of baseline",
+			Field:      "ResponseTimes.Mean",
+			Predicate:  "LTE",
+			RelativeTo: testsuite.Baseline,
+			Factor:     1.1,
+		}
+		if len(file.Config.Tests) != 1 || file.Config.Tests[0].Name != "no failure" {
+			t.Errorf("exp engine test no failure, got %v", file.Config.Tests)
+		}
+		if !reflect.DeepEqual(file.RelativeTests, []testsuite.RelativeCase{mean}) {
+			t.Errorf("exp relative test %v, got %v", mean, file.RelativeTests)
+		}
+
+		inherited, relativeOnly := file.Scenarios[0], file.Scenarios[1]
+		if len(inherited.Config.Tests) != 1 || !reflect.DeepEqual(inherited.RelativeTests, file.RelativeTests) {
+			t.Errorf("inherited: exp top-level tests, got %v and %v", inherited.Config.Tests, inherited.RelativeTests)
+		}
+
+		p99 := testsuite.RelativeCase{
+			Name:       "p99 under 3x p50",
+			Field:      "ResponseTimes.P99",
+			Predicate:  "LT",
+			RelativeTo: "ResponseTimes.P50",
+			Factor:     3,
+		}
+		if len(relativeOnly.Config.Tests) != 0 {
+			t.Errorf("relative-only: exp no engine tests, got %v", relativeOnly.Config.Tests)
+		}
+		if !reflect.DeepEqual(relativeOnly.RelativeTests, []testsuite.RelativeCase{p99}) {
+			t.Errorf("relative-only: exp relative test %v, got %v", p99, relativeOnly.RelativeTests)
+		}
+	})
+
+	t.Run("return ErrParse for invalid relative tests", func(t *testing.T) {
+		_, err := configfile.ParseFile(configPath("relative/invalid.yml"))
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
+}
+
+func TestParseFile_severity(t *testing.T) {
+	t.Run("collect the names of the tests of severity warn", func(t *testing.T) {
+		file, err := configfile.ParseFile(configPath("severity/tests.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := testsuite.Warnings{"aspirational mean", "aspirational p99"}
+		if !reflect.DeepEqual(file.Warnings, exp) {
+			t.Errorf("exp warnings %v, got %v", exp, file.Warnings)
+		}
+		if len(file.Config.Tests) != 2 || len(file.RelativeTests) != 1 {
+			t.Errorf("exp 2 engine tests and 1 relative test, got %v and %v", file.Config.Tests, file.RelativeTests)
+		}
+
+		inherited, strict := file.Scenarios[0], file.Scenarios[1]
+		if !reflect.DeepEqual(inherited.Warnings, exp) {
+			t.Errorf("inherited: exp warnings %v, got %v", exp, inherited.Warnings)
+		}
+		if len(strict.Warnings) != 0 {
+			t.Errorf("strict: exp no warnings, got %v", strict.Warnings)
+		}
+	})
+
+	t.Run("return ErrParse for unknown severities", func(t *testing.T) {
+		_, err := configfile.ParseFile(configPath("severity/invalid.yml"))
+		if !errors.Is(err, configfile.ErrParse) {
+			t.Errorf("exp ErrParse, got %v", err)
+		}
+	})
 }
 
 // helpers
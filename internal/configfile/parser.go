@@ -1,9 +1,17 @@
 package configfile
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 
-	"github.com/benchttp/engine/configparse"
+	"gopkg.in/yaml.v3"
 )
 
 type extension string
@@ -14,11 +22,17 @@ const (
 	extJSON extension = ".json"
 )
 
+// Extensions lists the extensions of the supported config file formats.
+var Extensions = []string{string(extYML), string(extYAML), string(extJSON)}
+
 // configParser exposes a method parse to read bytes as a raw config.
 type configParser interface {
-	// parse parses a raw bytes input as a raw config and stores
-	// the resulting value into dst.
-	Parse(in []byte, dst *configparse.Representation) error
+	// parse parses a raw bytes input as a raw config, interpolating the
+	// references to environment variables with lookupEnv, and stores
+	// the resulting value into dst. If referenced variables are not set
+	// and have no default value, it returns their names and leaves dst
+	// untouched.
+	Parse(in []byte, dst *Representation, lookupEnv func(string) (string, bool)) (undefined []string, err error)
 }
 
 // newParser returns an appropriate parser according to ext, or a non-nil
@@ -26,10 +40,253 @@ type configParser interface {
 func newParser(ext extension) (configParser, error) {
 	switch ext {
 	case extYML, extYAML:
-		return configparse.YAMLParser{}, nil
+		return yamlParser{}, nil
 	case extJSON:
-		return configparse.JSONParser{}, nil
+		return jsonParser{}, nil
 	default:
 		return nil, errors.New("unsupported config format")
 	}
 }
+
+// yamlParser implements configParser for YAML config files.
+// It mirrors configparse.YAMLParser for the extended Representation:
+// unknown fields are rejected, except custom fields prefixed with "x-".
+type yamlParser struct{}
+
+// Parse decodes a raw yaml input in strict mode (unknown fields disallowed)
+// and stores the resulting value into dst. Only the scalar values of the
+// document are interpolated: references in its keys and comments are
+// left as is.
+func (p yamlParser) Parse(
+	in []byte,
+	dst *Representation,
+	lookupEnv func(string) (string, bool),
+) ([]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(in, &doc); err != nil {
+		return nil, p.handleError(err)
+	}
+	if doc.Kind == 0 {
+		return nil, io.EOF
+	}
+
+	if undefined := interpolateNode(&doc, lookupEnv); len(undefined) != 0 {
+		return undefined, nil
+	}
+
+	// a node is decoded without checking the known fields:
+	// check them beforehand
+	unknown := unknownFields(&doc, reflect.TypeOf(dst))
+	err := doc.Decode(dst)
+
+	var typeError *yaml.TypeError
+	switch {
+	case errors.As(err, &typeError):
+		typeError.Errors = append(unknown, typeError.Errors...)
+		sort.SliceStable(typeError.Errors, func(i, j int) bool {
+			return errorLine(typeError.Errors[i]) < errorLine(typeError.Errors[j])
+		})
+	case err == nil && len(unknown) != 0:
+		err = &yaml.TypeError{Errors: unknown}
+	}
+	return nil, p.handleError(err)
+}
+
+// errorLine returns the line of a yaml decoding error message,
+// or 0 if it has none.
+func errorLine(msg string) int {
+	var line int
+	fmt.Sscanf(msg, "line %d:", &line) //nolint:errcheck // 0 if missing
+	return line
+}
+
+// unknownFields returns the errors of the mapping keys of node that are
+// not fields of typ, in the format of the ones of a yaml.Decoder
+// with known fields.
+func unknownFields(node *yaml.Node, typ reflect.Type) []string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var errs []string
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, child := range node.Content {
+			errs = append(errs, unknownFields(child, typ)...)
+		}
+	case node.Kind == yaml.AliasNode:
+		errs = append(errs, unknownFields(node.Alias, typ)...)
+	case node.Kind == yaml.SequenceNode && typ.Kind() == reflect.Struct:
+		// sequence of merged mappings
+		for _, child := range node.Content {
+			errs = append(errs, unknownFields(child, typ)...)
+		}
+	case node.Kind == yaml.SequenceNode && typ.Kind() == reflect.Slice:
+		for _, child := range node.Content {
+			errs = append(errs, unknownFields(child, typ.Elem())...)
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Map:
+		for i := 1; i < len(node.Content); i += 2 {
+			errs = append(errs, unknownFields(node.Content[i], typ.Elem())...)
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Struct:
+		fields := yamlFields(typ)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				// merged mappings are fields of the same type
+				errs = append(errs, unknownFields(value, typ)...)
+				continue
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("line %d: field %s not found in type %s", key.Line, key.Value, typ))
+				continue
+			}
+			errs = append(errs, unknownFields(value, field.typ)...)
+		}
+	}
+	return errs
+}
+
+// handleError handles an error from package yaml, transforming it
+// into a user-friendly standardized format.
+func (p yamlParser) handleError(err error) error {
+	var typeError *yaml.TypeError
+	if !errors.As(err, &typeError) {
+		return err
+	}
+
+	filtered := &yaml.TypeError{}
+	for _, msg := range typeError.Errors {
+		if yamlCustomFieldRgx.MatchString(msg) {
+			continue
+		}
+		filtered.Errors = append(filtered.Errors, p.prettyErrorMessage(msg))
+	}
+
+	if len(filtered.Errors) != 0 {
+		return filtered
+	}
+
+	return nil
+}
+
+var (
+	yamlCustomFieldRgx = regexp.MustCompile(
+		`^line \d+: field (x-\S+) not found in type`,
+	)
+	yamlFieldNotFoundRgx = regexp.MustCompile(
+		`^line (\d+): field (\S+) not found in type`,
+	)
+	yamlFieldBadValueRgx = regexp.MustCompile(
+		`^line (\d+): cannot unmarshal !!\w+(?: ` + "`" + `(\S+)` + "`" + `)? into (\S+)$`,
+	)
+)
+
+// prettyErrorMessage transforms a raw Decode error message into a more
+// user-friendly one by removing noisy information and adding quotes.
+func (p yamlParser) prettyErrorMessage(raw string) string {
+	if matches := yamlFieldNotFoundRgx.FindStringSubmatch(raw); len(matches) >= 3 {
+		line, field := matches[1], matches[2]
+		return fmt.Sprintf(`line %s: invalid field ("%s"): does not exist`, line, field)
+	}
+
+	if matches := yamlFieldBadValueRgx.FindStringSubmatch(raw); len(matches) >= 3 {
+		line, value, exptype := matches[1], matches[2], matches[3]
+		if value == "" {
+			return fmt.Sprintf("line %s: wrong type: want %s", line, exptype)
+		}
+		return fmt.Sprintf(`line %s: wrong type ("%s"): want %s`, line, value, exptype)
+	}
+
+	return raw
+}
+
+// jsonParser implements configParser for JSON config files.
+// It mirrors configparse.JSONParser for the extended Representation:
+// unknown fields are rejected, except custom fields prefixed with "x-".
+type jsonParser struct{}
+
+// Parse decodes a raw JSON input in strict mode (unknown fields disallowed)
+// and stores the resulting value into dst. Only the string values of the
+// document are interpolated, once decoded: the values of the variables
+// cannot alter its structure. References in place of numbers are decoded
+// as numbers.
+func (p jsonParser) Parse(
+	in []byte,
+	dst *Representation,
+	lookupEnv func(string) (string, bool),
+) ([]string, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(in))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, p.handleError(err)
+	}
+
+	doc, undefined := interpolateJSON(doc, reflect.TypeOf(dst), lookupEnv)
+	if len(undefined) != 0 {
+		return undefined, nil
+	}
+	removeCustomFields(doc, reflect.TypeOf(dst))
+
+	b, _ := json.Marshal(doc) // decoded documents are always encodable
+	decoder = json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	return nil, p.handleError(decoder.Decode(dst))
+}
+
+// removeCustomFields removes the custom fields prefixed with "x-" of
+// the objects of the decoded JSON document v decoded into structs of
+// typ, as yamlParser ignores them. The keys of the other objects, such
+// as the names of headers, are left as is.
+func removeCustomFields(v interface{}, typ reflect.Type) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if typ != nil && typ.Kind() == reflect.Struct && strings.HasPrefix(key, "x-") {
+				delete(v, key)
+				continue
+			}
+			removeCustomFields(child, childType(typ, key))
+		}
+	case []interface{}:
+		for _, child := range v {
+			removeCustomFields(child, childType(typ, ""))
+		}
+	}
+}
+
+var jsonUnknownFieldRgx = regexp.MustCompile(`json: unknown field "(\S+)"`)
+
+// handleError handle a JSON decoding error, transforming it
+// into a user-friendly standardized format.
+func (p jsonParser) handleError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errSyntax *json.SyntaxError
+	if errors.As(err, &errSyntax) {
+		return fmt.Errorf("syntax error near %d: %w", errSyntax.Offset, err)
+	}
+
+	var errType *json.UnmarshalTypeError
+	if errors.As(err, &errType) {
+		return fmt.Errorf(
+			"wrong type for field %s: want %s, got %s",
+			errType.Field, errType.Type, errType.Value,
+		)
+	}
+
+	if matches := jsonUnknownFieldRgx.FindStringSubmatch(err.Error()); len(matches) >= 2 {
+		return fmt.Errorf(`invalid field ("%s"): does not exist`, matches[1])
+	}
+
+	return err
+}
@@ -0,0 +1,162 @@
+package configfile
+
+import (
+	"github.com/benchttp/engine/configparse"
+)
+
+// Representation is the raw representation of a config file.
+// It extends configparse.Representation with the options that are
+// specific to the CLI. The engine options keep the same shape, so any
+// file accepted by the engine is accepted as well.
+type Representation struct {
+	Extends *string `yaml:"extends,omitempty" json:"extends,omitempty"`
+
+	Request RequestRepresentation `yaml:"request,omitempty" json:"request,omitempty"`
+
+	Runner RunnerRepresentation `yaml:"runner,omitempty" json:"runner,omitempty"`
+
+	Tests []TestRepresentation `yaml:"tests,omitempty" json:"tests,omitempty"`
+
+	Scenarios []ScenarioRepresentation `yaml:"scenarios,omitempty" json:"scenarios,omitempty"`
+
+	Baseline *BaselineRepresentation `yaml:"baseline,omitempty" json:"baseline,omitempty"`
+
+	Redact *RedactRepresentation `yaml:"redact,omitempty" json:"redact,omitempty"`
+}
+
+// RedactRepresentation is the raw representation of the locations
+// of sensitive values, redacted in addition to the ones of flag -redact.
+// The patterns have the formats of redact.Rules.
+type RedactRepresentation struct {
+	Header []string `yaml:"header,omitempty" json:"header,omitempty"`
+	Query  []string `yaml:"query,omitempty" json:"query,omitempty"`
+	Body   []string `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// BaselineRepresentation is the raw representation of the comparison
+// with a baseline report.
+type BaselineRepresentation struct {
+	// Tolerances maps metric fields to the regression allowed
+	// for them, such as "10%", "50ms" or 2.
+	Tolerances map[string]interface{} `yaml:"tolerances,omitempty" json:"tolerances,omitempty"`
+}
+
+// ScenarioRepresentation is the raw representation of a scenario.
+// Its options override the top-level ones of the config file.
+type ScenarioRepresentation struct {
+	Name *string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	Request RequestRepresentation `yaml:"request,omitempty" json:"request,omitempty"`
+
+	Runner RunnerRepresentation `yaml:"runner,omitempty" json:"runner,omitempty"`
+
+	Tests []TestRepresentation `yaml:"tests,omitempty" json:"tests,omitempty"`
+}
+
+// RequestRepresentation is the raw representation of the request options.
+type RequestRepresentation struct {
+	Method      *string             `yaml:"method,omitempty" json:"method,omitempty"`
+	URL         *string             `yaml:"url,omitempty" json:"url,omitempty"`
+	QueryParams map[string]string   `yaml:"queryParams,omitempty" json:"queryParams,omitempty"`
+	Header      map[string][]string `yaml:"header,omitempty" json:"header,omitempty"`
+	Body        *BodyRepresentation `yaml:"body,omitempty" json:"body,omitempty"`
+	Auth        *AuthRepresentation `yaml:"auth,omitempty" json:"auth,omitempty"`
+}
+
+// BodyRepresentation is the raw representation of the request body.
+type BodyRepresentation struct {
+	Type    string `yaml:"type" json:"type"`
+	Content string `yaml:"content" json:"content"`
+}
+
+// AuthRepresentation is the raw representation of the request
+// credentials. Token and Password accept the formats of auth.Source.
+type AuthRepresentation struct {
+	Type     string `yaml:"type" json:"type"`
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	Token    string `yaml:"token,omitempty" json:"token,omitempty"`
+}
+
+// RunnerRepresentation is the raw representation of the runner options.
+type RunnerRepresentation struct {
+	Requests       *int    `yaml:"requests,omitempty" json:"requests,omitempty"`
+	Concurrency    *int    `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	Interval       *string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	RequestTimeout *string `yaml:"requestTimeout,omitempty" json:"requestTimeout,omitempty"`
+	GlobalTimeout  *string `yaml:"globalTimeout,omitempty" json:"globalTimeout,omitempty"`
+}
+
+// TestRepresentation is the raw representation of a test case.
+// Its target is either an absolute value, or an object describing
+// a target relative to another metric, run by the CLI rather than
+// the engine: see RelativeTargetRepresentation.
+type TestRepresentation struct {
+	Name      *string     `yaml:"name,omitempty" json:"name,omitempty"`
+	Field     *string     `yaml:"field,omitempty" json:"field,omitempty"`
+	Predicate *string     `yaml:"predicate,omitempty" json:"predicate,omitempty"`
+	Target    interface{} `yaml:"target" json:"target"`
+
+	// Severity is "error" by default, or "warn" for a test whose
+	// failure is reported without failing the run.
+	Severity *string `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// RelativeTargetRepresentation is the raw representation of a target
+// relative to another metric.
+type RelativeTargetRepresentation struct {
+	// RelativeTo is "baseline" for the value of the tested field in the
+	// baseline report, or another field of the report.
+	RelativeTo *string `yaml:"relativeTo" json:"relativeTo"`
+
+	// Factor multiplies the reference value into the target.
+	// It defaults to 1.
+	Factor *float64 `yaml:"factor,omitempty" json:"factor,omitempty"`
+}
+
+// isRelative returns true if the target of t is relative to another
+// metric, i.e. an object rather than a value.
+func (t TestRepresentation) isRelative() bool {
+	_, ok := t.Target.(map[string]interface{})
+	return ok
+}
+
+// engineRepresentation returns the configparse.Representation
+// of the options known by the engine. The tests with a relative target
+// are left out.
+func (repr Representation) engineRepresentation() configparse.Representation {
+	var e configparse.Representation
+
+	e.Extends = repr.Extends
+
+	e.Request.Method = repr.Request.Method
+	e.Request.URL = repr.Request.URL
+	e.Request.QueryParams = repr.Request.QueryParams
+	e.Request.Header = repr.Request.Header
+	if body := repr.Request.Body; body != nil {
+		e.Request.Body = &struct {
+			Type    string `yaml:"type" json:"type"`
+			Content string `yaml:"content" json:"content"`
+		}{Type: body.Type, Content: body.Content}
+	}
+
+	e.Runner.Requests = repr.Runner.Requests
+	e.Runner.Concurrency = repr.Runner.Concurrency
+	e.Runner.Interval = repr.Runner.Interval
+	e.Runner.RequestTimeout = repr.Runner.RequestTimeout
+	e.Runner.GlobalTimeout = repr.Runner.GlobalTimeout
+
+	for _, t := range repr.Tests {
+		if t.isRelative() {
+			continue
+		}
+		e.Tests = append(e.Tests, struct {
+			Name      *string     `yaml:"name" json:"name"`
+			Field     *string     `yaml:"field" json:"field"`
+			Predicate *string     `yaml:"predicate" json:"predicate"`
+			Target    interface{} `yaml:"target" json:"target"`
+		}{Name: t.Name, Field: t.Field, Predicate: t.Predicate, Target: t.Target})
+	}
+
+	return e
+}
@@ -0,0 +1,9 @@
+package configfile
+
+import _ "embed" // for go:embed
+
+// Schema is the JSON Schema of config files. It can be used by editors
+// to validate and autocomplete config files.
+//
+//go:embed schema.json
+var Schema []byte
@@ -0,0 +1,150 @@
+package configfile_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchttp/cli/internal/configfile"
+)
+
+func TestSchema(t *testing.T) {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(configfile.Schema))
+	if err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+
+	t.Run("validate example and valid config files", func(t *testing.T) {
+		examples, _ := filepath.Glob("../../examples/config/*.yml")
+		valid, _ := filepath.Glob("testdata/valid/*")
+		files := append(append(examples, valid...),
+			"testdata/scenarios/journey.yml",
+			"testdata/baseline/parent.yml",
+			"testdata/baseline/child.yml",
+			"testdata/redact/parent.yml",
+			"testdata/redact/child.yml",
+			"testdata/env/interpolate.yml",
+			"testdata/relative/tests.yml",
+			"testdata/severity/tests.yml",
+		)
+		if len(examples) == 0 {
+			t.Fatal("no example config file found")
+		}
+
+		for _, filename := range files {
+			result, err := schema.Validate(gojsonschema.NewGoLoader(readConfigDocument(t, filename)))
+			if err != nil {
+				t.Fatalf("%s: %v", filename, err)
+			}
+			if !result.Valid() {
+				t.Errorf("%s: %v", filename, result.Errors())
+			}
+		}
+	})
+
+	t.Run("invalidate config files with bad fields", func(t *testing.T) {
+		for _, filename := range []string{
+			"testdata/invalid/badfields.yml",
+			"testdata/invalid/badfields.json",
+			"testdata/severity/invalid.yml",
+		} {
+			result, err := schema.Validate(gojsonschema.NewGoLoader(readConfigDocument(t, filename)))
+			if err != nil {
+				t.Fatalf("%s: %v", filename, err)
+			}
+			if result.Valid() {
+				t.Errorf("%s: exp invalid", filename)
+			}
+		}
+	})
+
+	t.Run("describe every field of Representation", func(t *testing.T) {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(configfile.Schema, &doc); err != nil {
+			t.Fatal(err)
+		}
+		definitions := doc["definitions"].(map[string]interface{})
+
+		assertSameKeys(t, "root", doc, configfile.Representation{})
+		assertSameKeys(t, "request", definitions["request"], configfile.RequestRepresentation{})
+		assertSameKeys(t, "runner", definitions["runner"], configfile.RunnerRepresentation{})
+		assertSameKeys(t, "test", definitions["test"], configfile.TestRepresentation{})
+		assertSameKeys(t, "scenario", definitions["scenario"], configfile.ScenarioRepresentation{})
+		assertSameKeys(t, "baseline", definitions["baseline"], configfile.BaselineRepresentation{})
+		assertSameKeys(t, "redact", definitions["redact"], configfile.RedactRepresentation{})
+		assertSameKeys(t, "relativeTarget", definitions["relativeTarget"], configfile.RelativeTargetRepresentation{})
+
+		requestProps := definitions["request"].(map[string]interface{})["properties"].(map[string]interface{})
+		assertSameKeys(t, "request.body", requestProps["body"], configfile.BodyRepresentation{})
+		assertSameKeys(t, "request.auth", requestProps["auth"], configfile.AuthRepresentation{})
+	})
+
+	t.Run("enumerate valid metric fields", func(t *testing.T) {
+		var doc struct {
+			Definitions struct {
+				Test struct {
+					Properties struct {
+						Field struct {
+							AnyOf []struct {
+								Enum []string
+							}
+						}
+					}
+				}
+			}
+		}
+		if err := json.Unmarshal(configfile.Schema, &doc); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range doc.Definitions.Test.Properties.Field.AnyOf[0].Enum {
+			if err := runner.MetricsField(field).Validate(); err != nil {
+				t.Errorf("invalid metric field in schema: %v", err)
+			}
+		}
+	})
+}
+
+// readConfigDocument returns the content of a YAML or JSON config file
+// as a generic document.
+func readConfigDocument(t *testing.T, filename string) interface{} {
+	t.Helper()
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil { // YAML is a superset of JSON
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return doc
+}
+
+// assertSameKeys asserts that the properties of the schema object
+// match the yaml keys of the struct v.
+func assertSameKeys(t *testing.T, name string, schemaObject interface{}, v interface{}) {
+	t.Helper()
+
+	props := schemaObject.(map[string]interface{})["properties"].(map[string]interface{})
+
+	typ := reflect.TypeOf(v)
+	keys := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		key := strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0]
+		keys[key] = true
+		if _, ok := props[key]; !ok {
+			t.Errorf("%s: missing property %s in schema", name, key)
+		}
+	}
+	for key := range props {
+		if !keys[key] {
+			t.Errorf("%s: unknown property %s in schema", name, key)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// Comments maps the path of keys of a config file, such as "runner"
+// or "runner.requests", to comments written along with them.
+// The empty path sets the head comment of the document.
+type Comments map[string]string
+
+// Marshal returns the encoding of repr in the format matching
+// the extension of filename, or ErrFileExt for an unsupported extension.
+func Marshal(filename string, repr Representation) ([]byte, error) {
+	return MarshalComments(filename, repr, nil)
+}
+
+// MarshalComments is like Marshal but writes comments along with
+// the keys of repr. Comments are ignored for JSON files.
+func MarshalComments(filename string, repr Representation, comments Comments) ([]byte, error) {
+	switch ext := extension(filepath.Ext(filename)); ext {
+	case extYML, extYAML:
+		var node yaml.Node
+		if err := node.Encode(repr); err != nil {
+			return nil, err
+		}
+		setComments(&node, "", comments)
+		doc := yaml.Node{
+			Kind:        yaml.DocumentNode,
+			Content:     []*yaml.Node{&node},
+			HeadComment: comments[""],
+		}
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return separateSections(buf.Bytes()), nil
+	case extJSON:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false) // as benchttp fmt
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(repr); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errorutil.WithDetails(ErrFileExt, ext)
+	}
+}
+
+// setComments sets the comments of the keys of the mapping node
+// at the given path, recursively. Keys of nested mappings get a head
+// comment, other keys a line comment.
+func setComments(node *yaml.Node, path string, comments Comments) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := strings.TrimPrefix(path+"."+key.Value, ".")
+		if c, ok := comments[keyPath]; ok {
+			if value.Kind == yaml.ScalarNode {
+				value.LineComment = c
+			} else {
+				key.HeadComment = c
+			}
+		}
+		setComments(value, keyPath, comments)
+	}
+}
+
+// Write writes repr to filename in the format matching its extension,
+// creating the parent directories if needed. An existing file is
+// overwritten.
+func Write(filename string, repr Representation) error {
+	return WriteComments(filename, repr, nil)
+}
+
+// WriteComments is like Write but writes comments along with
+// the keys of repr. Comments are ignored for JSON files.
+func WriteComments(filename string, repr Representation, comments Comments) error {
+	b, err := MarshalComments(filename, repr, comments)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0o600)
+}
@@ -0,0 +1,124 @@
+package configfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/configfile"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("write files readable by Parse", func(t *testing.T) {
+		var (
+			method   = "PUT"
+			rawURL   = "http://localhost:9999?a=b"
+			requests = 0
+			interval = "50ms"
+		)
+
+		repr := configfile.Representation{}
+		repr.Request.Method = &method
+		repr.Request.URL = &rawURL
+		repr.Request.Header = map[string][]string{"key0": {"val0"}}
+		repr.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: `{"a":"b"}`}
+		repr.Runner.Requests = &requests
+		repr.Runner.Interval = &interval
+
+		for _, ext := range supportedExt {
+			filename := filepath.Join(t.TempDir(), "nested", "benchttp"+ext)
+
+			if err := configfile.Write(filename, repr); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := configfile.Parse(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg.Request.Method != method || cfg.Request.URL.String() != rawURL ||
+				cfg.Request.Header["key0"][0] != "val0" ||
+				string(cfg.Request.Body.Content) != `{"a":"b"}` ||
+				cfg.Runner.Requests != requests || cfg.Runner.Interval.String() != interval {
+				t.Errorf("%s: unexpected parsed config: %v", ext, cfg)
+			}
+		}
+	})
+
+	t.Run("write comments in yaml files", func(t *testing.T) {
+		requests := 10
+		repr := configfile.Representation{}
+		repr.Runner.Requests = &requests
+
+		filename := filepath.Join(t.TempDir(), "benchttp.yml")
+		if err := configfile.WriteComments(filename, repr, configfile.Comments{
+			"":                "generated",
+			"runner":          "load parameters",
+			"runner.requests": "total requests",
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := "# generated\n\n# load parameters\nrunner:\n  requests: 10 # total requests\n"
+		if got := string(b); got != exp {
+			t.Errorf("bad output:\nexp %q\ngot %q", exp, got)
+		}
+
+		if _, err := configfile.Parse(filename); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("ignore comments in json files", func(t *testing.T) {
+		b, err := configfile.MarshalComments("benchttp.json", configfile.Representation{}, configfile.Comments{"": "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), "x") {
+			t.Errorf("unexpected comment in json: %s", b)
+		}
+	})
+
+	t.Run("write files in canonical format", func(t *testing.T) {
+		var (
+			rawURL   = "http://localhost:9999?a=<b>&c"
+			requests = 10
+			timeout  = "1m"
+		)
+
+		repr := configfile.Representation{}
+		repr.Request.URL = &rawURL
+		repr.Runner.Requests = &requests
+		repr.Runner.GlobalTimeout = &timeout
+
+		for _, ext := range supportedExt {
+			b, err := configfile.MarshalComments("benchttp"+ext, repr, configfile.Comments{
+				"request": "request", "runner": "runner",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			formatted, err := configfile.Format(b, ext, ext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != string(formatted) {
+				t.Errorf("%s: not formatted:\nexp %q\ngot %q", ext, formatted, b)
+			}
+		}
+	})
+
+	t.Run("return ErrFileExt", func(t *testing.T) {
+		err := configfile.Write(filepath.Join(t.TempDir(), "benchttp.toml"), configfile.Representation{})
+		if !errors.Is(err, configfile.ErrFileExt) {
+			t.Errorf("exp ErrFileExt, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package configflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/benchttp/cli/internal/auth"
+)
+
+const (
+	flagBasicAuth = "basicAuth"
+	flagBearer    = "bearer"
+)
+
+// BindAuth binds the authentication flags of flagset to dst.
+// The credentials are not resolved at parse time, so they can reference
+// environment variables that are loaded afterwards.
+// The provided *flag.Flagset must not have been parsed yet, otherwise
+// bindings its values would fail.
+func BindAuth(flagset *flag.FlagSet, dst *auth.Auth) {
+	flagset.Var(basicAuthValue{auth: dst},
+		flagBasicAuth,
+		`HTTP Basic authentication in format "user:password", `+
+			`password can be read from "env:NAME" or "file:PATH"`,
+	)
+	flagset.Var(basicAuthValue{auth: dst}, "u", "Alias for -basicAuth (curl)")
+	flagset.Var(basicAuthValue{auth: dst}, "user", "Alias for -basicAuth (curl)")
+	flagset.Var(bearerValue{auth: dst},
+		flagBearer,
+		`HTTP Bearer token, can be read from "env:NAME" or "file:PATH"`,
+	)
+}
+
+// basicAuthValue implements flag.Value
+type basicAuthValue struct {
+	auth *auth.Auth
+}
+
+// String returns a string representation of the referenced credentials,
+// without the password.
+func (v basicAuthValue) String() string {
+	if v.auth == nil || v.auth.Type != auth.Basic {
+		return ""
+	}
+	return v.auth.Username
+}
+
+// Set reads input string in format "user:password" and sets the
+// referenced credentials accordingly.
+func (v basicAuthValue) Set(raw string) error {
+	userpass := strings.SplitN(raw, ":", 2)
+	if len(userpass) != 2 || userpass[0] == "" {
+		return errors.New(`expect format "<user>:<password>"`)
+	}
+	*v.auth = auth.Auth{
+		Type:     auth.Basic,
+		Username: userpass[0],
+		Password: auth.Source(userpass[1]),
+	}
+	return nil
+}
+
+// bearerValue implements flag.Value
+type bearerValue struct {
+	auth *auth.Auth
+}
+
+// String returns an empty string, as the token must not be displayed.
+func (v bearerValue) String() string {
+	return ""
+}
+
+// Set sets the referenced credentials with input string as the token.
+func (v bearerValue) Set(raw string) error {
+	if raw == "" {
+		return errors.New("expect non-empty token")
+	}
+	*v.auth = auth.Auth{
+		Type:  auth.Bearer,
+		Token: auth.Source(raw),
+	}
+	return nil
+}
@@ -22,14 +22,20 @@ func Bind(flagset *flag.FlagSet, dst *runner.Config) {
 	}
 
 	// request url
-	flagset.Var(urlValue{url: dst.Request.URL},
+	query := url.Values{}
+	flagset.Var(urlValue{url: dst.Request.URL, query: query},
 		runner.ConfigFieldURL,
 		runner.ConfigFieldsUsage[runner.ConfigFieldURL],
 	)
+	// request query params
+	flagset.Var(queryValue{url: dst.Request.URL, query: query},
+		flagQuery,
+		`HTTP request query parameter in format "key=value"`,
+	)
 	// request method
-	flagset.StringVar(&dst.Request.Method,
+	explicitMethod := false
+	flagset.Var(methodValue{method: &dst.Request.Method, explicit: &explicitMethod},
 		runner.ConfigFieldMethod,
-		dst.Request.Method,
 		runner.ConfigFieldsUsage[runner.ConfigFieldMethod],
 	)
 	// request header
@@ -73,4 +79,7 @@ func Bind(flagset *flag.FlagSet, dst *runner.Config) {
 		dst.Runner.GlobalTimeout,
 		runner.ConfigFieldsUsage[runner.ConfigFieldGlobalTimeout],
 	)
+
+	// curl-compatible aliases
+	bindCurlAliases(flagset, dst, &explicitMethod)
 }
@@ -3,12 +3,16 @@ package configflag_test
 import (
 	"flag"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/benchttp/engine/runner"
 
+	"github.com/benchttp/cli/internal/auth"
 	"github.com/benchttp/cli/internal/configflag"
 )
 
@@ -67,4 +71,154 @@ func TestBind(t *testing.T) {
 			t.Errorf("\nexp %#v\ngot %#v", exp, cfg)
 		}
 	})
+
+	t.Run("set config with curl aliases", func(t *testing.T) {
+		datafile := filepath.Join(t.TempDir(), "data.txt")
+		if err := os.WriteFile(datafile, []byte("c=3\nd=4\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		testcases := []struct {
+			label     string
+			args      []string
+			expMethod string
+			expHeader http.Header
+			expBody   string
+		}{
+			{
+				label:     "header and method",
+				args:      []string{"-X", "PUT", "-H", "accept: text/html", "--request", "PATCH"},
+				expMethod: "PATCH",
+				expHeader: http.Header{"accept": {"text/html"}},
+			},
+			{
+				label:     "data implies POST",
+				args:      []string{"-d", "a=1", "--data", "b=2", "-d", "@" + datafile},
+				expMethod: "POST",
+				expBody:   "a=1&b=2&c=3d=4",
+			},
+			{
+				label:     "explicit method is kept",
+				args:      []string{"-X", "PUT", "--data-binary", "@" + datafile},
+				expMethod: "PUT",
+				expBody:   "c=3\nd=4\n",
+			},
+			{
+				label:     "raw data",
+				args:      []string{"--data-raw", "@notafile", "-method", "GET"},
+				expMethod: "GET",
+				expBody:   "@notafile",
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.label, func(t *testing.T) {
+				flagset := flag.NewFlagSet("run", flag.ContinueOnError)
+
+				cfg := runner.Config{}
+				configflag.Bind(flagset, &cfg)
+				if err := flagset.Parse(tc.args); err != nil {
+					t.Fatal(err) // critical error, stop the test
+				}
+
+				if cfg.Request.Method != tc.expMethod {
+					t.Errorf("method: exp %q, got %q", tc.expMethod, cfg.Request.Method)
+				}
+				if tc.expHeader != nil && !reflect.DeepEqual(cfg.Request.Header, tc.expHeader) {
+					t.Errorf("header:\nexp %v\ngot %v", tc.expHeader, cfg.Request.Header)
+				}
+				if got := string(cfg.Request.Body.Content); got != tc.expBody {
+					t.Errorf("body: exp %q, got %q", tc.expBody, got)
+				}
+			})
+		}
+	})
+
+	t.Run("trim spaces around header values", func(t *testing.T) {
+		flagset := flag.NewFlagSet("run", flag.ContinueOnError)
+
+		cfg := runner.Config{Request: runner.RequestConfig{Header: http.Header{}}}
+		configflag.Bind(flagset, &cfg)
+		if err := flagset.Parse([]string{"-header", "key0: val0 ", "-header", "key1:val1"}); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := http.Header{"key0": {"val0"}, "key1": {"val1"}}
+		if !reflect.DeepEqual(cfg.Request.Header, exp) {
+			t.Errorf("header:\nexp %v\ngot %v", exp, cfg.Request.Header)
+		}
+	})
+
+	t.Run("add query params to the url", func(t *testing.T) {
+		flagset := flag.NewFlagSet("run", flag.ExitOnError)
+		args := []string{
+			"-query", "q=a b&c",
+			"-url", "https://benchttp.app?z=1&cool=a%20b",
+			"-query", "cool=no",
+		}
+
+		cfg := runner.Config{Request: runner.RequestConfig{}.WithURL("http://localhost")}
+		configflag.Bind(flagset, &cfg)
+		if err := flagset.Parse(args); err != nil {
+			t.Fatal(err) // critical error, stop the test
+		}
+
+		exp := "https://benchttp.app?z=1&cool=a%20b&q=a+b%26c&cool=no"
+		if got := cfg.Request.URL.String(); got != exp {
+			t.Errorf("\nexp %s\ngot %s", exp, got)
+		}
+	})
+
+	t.Run("expose query params set", func(t *testing.T) {
+		flagset := flag.NewFlagSet("run", flag.ExitOnError)
+		args := []string{"-query", "a=1", "-query", "a=2", "-query", "b="}
+
+		configflag.Bind(flagset, &runner.Config{})
+		if err := flagset.Parse(args); err != nil {
+			t.Fatal(err) // critical error, stop the test
+		}
+
+		exp := url.Values{"a": {"1", "2"}, "b": {""}}
+		if got := configflag.Query(flagset); !reflect.DeepEqual(got, exp) {
+			t.Errorf("\nexp %v\ngot %v", exp, got)
+		}
+	})
+}
+
+func TestBindAuth(t *testing.T) {
+	for _, tc := range []struct {
+		label string
+		args  []string
+		exp   auth.Auth
+	}{
+		{
+			label: "basic auth",
+			args:  []string{"-basicAuth", "alice:env:PASSWORD"},
+			exp:   auth.Auth{Type: auth.Basic, Username: "alice", Password: "env:PASSWORD"},
+		},
+		{
+			label: "bearer",
+			args:  []string{"-bearer", "file:token.txt"},
+			exp:   auth.Auth{Type: auth.Bearer, Token: "file:token.txt"},
+		},
+		{
+			label: "last flag wins",
+			args:  []string{"-bearer", "abc", "-basicAuth", "alice:pass"},
+			exp:   auth.Auth{Type: auth.Basic, Username: "alice", Password: "pass"},
+		},
+	} {
+		t.Run(tc.label, func(t *testing.T) {
+			flagset := flag.NewFlagSet("run", flag.ContinueOnError)
+
+			var got auth.Auth
+			configflag.BindAuth(flagset, &got)
+			if err := flagset.Parse(tc.args); err != nil {
+				t.Fatal(err) // critical error, stop the test
+			}
+
+			if got != tc.exp {
+				t.Errorf("\nexp %#v\ngot %#v", tc.exp, got)
+			}
+		})
+	}
 }
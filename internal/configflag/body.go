@@ -12,9 +12,13 @@ type bodyValue struct {
 	body *runner.RequestBody
 }
 
-// String returns a string representation of the referenced body.
+// String returns a string representation of the referenced body,
+// or an empty string if it has no content.
 func (v bodyValue) String() string {
-	return fmt.Sprint(v.body)
+	if v.body == nil || len(v.body.Content) == 0 {
+		return ""
+	}
+	return fmt.Sprint(*v.body)
 }
 
 // Set reads input string in format "type:content" and sets
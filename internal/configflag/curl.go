@@ -0,0 +1,161 @@
+package configflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// curlAliases maps the curl-compatible flag names to the config field
+// they set.
+var curlAliases = map[string]string{
+	"H":           runner.ConfigFieldHeader,
+	"X":           runner.ConfigFieldMethod,
+	"request":     runner.ConfigFieldMethod,
+	"d":           runner.ConfigFieldBody,
+	"data":        runner.ConfigFieldBody,
+	"data-ascii":  runner.ConfigFieldBody,
+	"data-raw":    runner.ConfigFieldBody,
+	"data-binary": runner.ConfigFieldBody,
+}
+
+// isMethodFlag returns true if name is a flag that sets the method
+// explicitly.
+func isMethodFlag(name string) bool {
+	return name == runner.ConfigFieldMethod || curlAliases[name] == runner.ConfigFieldMethod
+}
+
+// bindCurlAliases attaches curl-compatible aliases of the request flags
+// to flagset. Like curl, setting a body without an explicit method
+// sets the method to POST.
+func bindCurlAliases(flagset *flag.FlagSet, dst *runner.Config, explicitMethod *bool) {
+	header := headerValue{header: &dst.Request.Header}
+	method := methodValue{method: &dst.Request.Method, explicit: explicitMethod}
+
+	flagset.Var(header, "H", "Alias for -header (curl)")
+	flagset.Var(method, "X", "Alias for -method (curl)")
+	flagset.Var(method, "request", "Alias for -method (curl)")
+
+	for _, alias := range []struct {
+		name string
+		mode dataMode
+	}{
+		{"d", dataText},
+		{"data", dataText},
+		{"data-ascii", dataText},
+		{"data-binary", dataBinary},
+		{"data-raw", dataRaw},
+	} {
+		flagset.Var(
+			&dataValue{
+				body:           &dst.Request.Body,
+				method:         &dst.Request.Method,
+				explicitMethod: explicitMethod,
+				mode:           alias.mode,
+			},
+			alias.name,
+			"Raw request body, alias for -body 'raw:<data>' (curl)",
+		)
+	}
+
+	// compression is negotiated by default, the flag is accepted
+	// so curl commands can be pasted as is
+	flagset.Bool("compressed", false, "No-op, accepted for curl compatibility")
+}
+
+// methodValue implements flag.Value
+type methodValue struct {
+	method   *string
+	explicit *bool
+}
+
+// String returns the referenced method.
+func (v methodValue) String() string {
+	if v.method == nil {
+		return ""
+	}
+	return *v.method
+}
+
+// Set sets the referenced method and marks it as explicitly set.
+func (v methodValue) Set(raw string) error {
+	*v.method = raw
+	*v.explicit = true
+	return nil
+}
+
+// dataMode determines how a curl data flag reads its input.
+type dataMode int
+
+const (
+	// dataText reads "@file" as a file with newlines stripped, like curl -d.
+	dataText dataMode = iota
+	// dataBinary reads "@file" as a file as is, like curl --data-binary.
+	dataBinary
+	// dataRaw reads the input as is, like curl --data-raw.
+	dataRaw
+)
+
+// dataValue implements flag.Value
+type dataValue struct {
+	body           *runner.RequestBody
+	method         *string
+	explicitMethod *bool
+	mode           dataMode
+}
+
+// String returns a string representation of the referenced body.
+func (v *dataValue) String() string {
+	if v.body == nil {
+		return ""
+	}
+	return string(v.body.Content)
+}
+
+// Set appends input data to the referenced raw body, separated by "&"
+// like curl does for repeated data flags, and sets the method to POST
+// if it was not set explicitly.
+func (v *dataValue) Set(raw string) error {
+	data, err := v.read(raw)
+	if err != nil {
+		return err
+	}
+
+	content := string(v.body.Content)
+	if v.body.Type == "raw" && content != "" {
+		content += "&"
+	}
+	*v.body = runner.NewRequestBody("raw", content+data)
+
+	if !*v.explicitMethod {
+		*v.method = "POST"
+	}
+	return nil
+}
+
+// read returns the data represented by the input string according
+// to the data mode.
+func (v *dataValue) read(raw string) (string, error) {
+	if v.mode == dataRaw || !strings.HasPrefix(raw, "@") {
+		return raw, nil
+	}
+
+	filename := strings.TrimPrefix(raw, "@")
+	if filename == "" {
+		return "", errors.New("expect a file name after @")
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("cannot read data file: %w", err)
+	}
+
+	if v.mode == dataText {
+		return strings.NewReplacer("\r", "", "\n", "").Replace(string(b)), nil
+	}
+	return string(b), nil
+}
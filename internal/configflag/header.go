@@ -12,19 +12,24 @@ type headerValue struct {
 	header *http.Header
 }
 
-// String returns a string representation of the referenced header.
+// String returns a string representation of the referenced header,
+// or an empty string if it has no values.
 func (v headerValue) String() string {
-	return fmt.Sprint(v.header)
+	if v.header == nil || len(*v.header) == 0 {
+		return ""
+	}
+	return fmt.Sprint(*v.header)
 }
 
 // Set reads input string in format "key:value" and appends value
-// to the key's values of the referenced header.
+// to the key's values of the referenced header. Like curl, it trims
+// the spaces around the value, so "key: value" is accepted as well.
 func (v headerValue) Set(raw string) error {
 	keyval := strings.SplitN(raw, ":", 2)
 	if len(keyval) != 2 {
 		return errors.New(`expect format "<key>:<value>"`)
 	}
-	key, val := keyval[0], keyval[1]
+	key, val := keyval[0], strings.TrimSpace(keyval[1])
 	(*v.header)[key] = append((*v.header)[key], val)
 	return nil
 }
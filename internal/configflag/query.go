@@ -0,0 +1,63 @@
+package configflag
+
+import (
+	"errors"
+	"flag"
+	"net/url"
+	"strings"
+)
+
+// flagQuery is the name of the flag for query parameters. It is not
+// a config field, as query parameters are part of the request URL.
+const flagQuery = "query"
+
+// queryValue implements flag.Value
+type queryValue struct {
+	query url.Values
+	url   *url.URL
+}
+
+// String returns a string representation of the query parameters set.
+func (v queryValue) String() string {
+	return v.query.Encode()
+}
+
+// Set reads input string in format "key=value" and adds value to the key's
+// values of the referenced URL query. Key and value are read unescaped,
+// the encoding is handled when the URL is built.
+func (v queryValue) Set(raw string) error {
+	keyval := strings.SplitN(raw, "=", 2)
+	if len(keyval) != 2 || keyval[0] == "" {
+		return errors.New(`expect format "<key>=<value>"`)
+	}
+	key, val := keyval[0], keyval[1]
+	v.query.Add(key, val)
+	AddQuery(v.url, url.Values{key: {val}})
+	return nil
+}
+
+// Query returns the query parameters set via the CLI for the given
+// *flag.FlagSet, or nil if none was set.
+func Query(flagset *flag.FlagSet) url.Values {
+	f := flagset.Lookup(flagQuery)
+	if f == nil {
+		return nil
+	}
+	if v, ok := f.Value.(queryValue); ok && len(v.query) != 0 {
+		return v.query
+	}
+	return nil
+}
+
+// AddQuery adds the query parameters q to the ones of u. Values of
+// an existing key are appended to the existing ones. The existing query
+// is left as is: the encoded parameters of q are appended to it.
+func AddQuery(u *url.URL, q url.Values) {
+	if u == nil || len(q) == 0 {
+		return
+	}
+	if u.RawQuery != "" {
+		u.RawQuery += "&"
+	}
+	u.RawQuery += q.Encode()
+}
@@ -8,6 +8,9 @@ import (
 // urlValue implements flag.Value
 type urlValue struct {
 	url *url.URL
+	// query is the query parameters set via flag -query, added to the
+	// parsed URL regardless of the order of the flags.
+	query url.Values
 }
 
 // String returns a string representation of urlValue.url.
@@ -25,5 +28,6 @@ func (v urlValue) Set(in string) error {
 		return fmt.Errorf(`invalid url: "%s"`, in)
 	}
 	*v.url = *urlURL
+	AddQuery(v.url, v.query)
 	return nil
 }
@@ -7,13 +7,39 @@ import (
 )
 
 // Which returns a slice of all config fields set via the CLI
-// for the given *flag.FlagSet.
+// for the given *flag.FlagSet, including via curl-compatible aliases.
 func Which(flagset *flag.FlagSet) []string {
-	var fields []string
+	var (
+		fields         []string
+		seen           = map[string]bool{}
+		explicitMethod bool
+		curlData       bool
+	)
+
+	add := func(field string) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
 	flagset.Visit(func(f *flag.Flag) {
-		if name := f.Name; runner.IsConfigField(name) {
-			fields = append(fields, name)
+		name := f.Name
+		if isMethodFlag(name) {
+			explicitMethod = true
+		}
+		if runner.IsConfigField(name) {
+			add(name)
+		} else if field, isAlias := curlAliases[name]; isAlias {
+			add(field)
+			curlData = curlData || field == runner.ConfigFieldBody
 		}
 	})
+
+	// like curl, data set without an explicit method implies POST
+	if curlData && !explicitMethod {
+		add(runner.ConfigFieldMethod)
+	}
+
 	return fields
 }
@@ -31,6 +31,21 @@ func TestWhich(t *testing.T) {
 				"requestTimeout", "requests", "url",
 			},
 		},
+		{
+			label: "return fields set via curl aliases",
+			args:  []string{"-H", "a:b", "-header", "c:d", "-X", "PUT"},
+			exp:   []string{"header", "method"},
+		},
+		{
+			label: "return method implied by curl data",
+			args:  []string{"-d", "a=b"},
+			exp:   []string{"body", "method"},
+		},
+		{
+			label: "do not imply method for flag body",
+			args:  []string{"-body", "raw:a=b"},
+			exp:   []string{"body"},
+		},
 		{
 			label: "do not return config flags not set",
 			args:  []string{"-requests", "3"},
@@ -0,0 +1,341 @@
+// Package dotenv reads environment variables declared in .env files.
+//
+// The supported syntax is a common subset of the existing .env formats:
+//
+//	# full-line comment
+//	KEY=value                # unquoted, trailing comment
+//	export KEY=value         # "export" prefix is ignored
+//	KEY='literal $value\n'   # single quotes: no escape sequence
+//	KEY="line 1\nline 2"     # double quotes: \n \r \t \" \\ \$ escapes
+//	KEY="multi
+//	line"                    # quoted values can span several lines
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+var (
+	// ErrFileNotFound signals an env file not found.
+	ErrFileNotFound = errors.New("env file not found")
+
+	// ErrFileRead signals an error trying to read an env file.
+	ErrFileRead = errors.New("invalid env file")
+
+	// ErrSyntax signals a malformed env file. Its details never include
+	// the values of the file, only line numbers and keys.
+	ErrSyntax = errors.New("env file syntax error")
+)
+
+// Env is the variables declared by an env file. They are not set in the
+// process environment, so that the env files of several config files
+// do not leak into one another: see Lookup.
+type Env map[string]string
+
+// Lookup returns the value of the environment variable of the given
+// name. Variables set in the process environment take precedence over
+// the ones of e.
+func (e Env) Lookup(name string) (string, bool) {
+	if val, ok := os.LookupEnv(name); ok {
+		return val, true
+	}
+	val, ok := e[name]
+	return val, ok
+}
+
+// secretKeyWords are the words of the names of the variables holding
+// credentials, see Secrets.
+var secretKeyWords = []string{
+	"TOKEN", "SECRET", "PASSWORD", "PASSWD", "PASS", "PWD",
+	"KEY", "AUTH", "CREDENTIAL", "COOKIE", "SESSION", "PRIVATE",
+}
+
+// Secrets returns the values of the variables of e whose name looks
+// like the one of a credential, such as API_TOKEN or DB_PASSWORD,
+// sorted. Other values, such as BASE_URL=http://localhost or
+// REQUESTS=1000, are common text that is not worth masking.
+func (e Env) Secrets() []string {
+	var secrets []string
+	for key, val := range e {
+		if isSecretKey(key) {
+			secrets = append(secrets, val)
+		}
+	}
+	sort.Strings(secrets)
+	return secrets
+}
+
+// isSecretKey returns true if the variable name key contains one of
+// secretKeyWords, case-insensitively.
+func isSecretKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, word := range secretKeyWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads the env file at filename and returns the variables
+// it declares.
+func Load(filename string) (Env, error) {
+	b, err := os.ReadFile(filename)
+	switch {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist):
+		return nil, errorutil.WithDetails(ErrFileNotFound, filename)
+	default:
+		return nil, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+
+	vars, err := Parse(string(b))
+	if err != nil {
+		return nil, errorutil.WithDetails(err, filename)
+	}
+	return vars, nil
+}
+
+// Parse parses the content of an env file and returns the declared
+// variables. If a key is declared several times, the last value wins.
+func Parse(in string) (map[string]string, error) {
+	p := parser{in: strings.ReplaceAll(in, "\r\n", "\n"), line: 1}
+	vars := map[string]string{}
+	for {
+		key, val, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return vars, nil
+		}
+		vars[key] = val
+	}
+}
+
+// parser is a stateful reader of an env file content.
+type parser struct {
+	in   string
+	pos  int
+	line int
+}
+
+// next reads the next declaration and returns its key and value.
+// It returns ok == false once the end of the input is reached.
+func (p *parser) next() (key, val string, ok bool, err error) {
+	for {
+		p.skip(" \t")
+		switch {
+		case p.done():
+			return "", "", false, nil
+		case p.peek() == '\n':
+			p.advance()
+			continue
+		case p.peek() == '#':
+			p.skipLine()
+			continue
+		}
+		break
+	}
+
+	key, err = p.readKey()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	val, err = p.readValue(key)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	return key, val, true, nil
+}
+
+// readKey reads a key and its following "=" sign.
+func (p *parser) readKey() (string, error) {
+	start := p.pos
+	for !p.done() && isKeyChar(p.peek()) {
+		p.advance()
+	}
+	key := p.in[start:p.pos]
+
+	if key == "export" && p.skip(" \t") > 0 {
+		return p.readKey()
+	}
+
+	if key == "" || isDigit(key[0]) {
+		return "", p.errorf("invalid key")
+	}
+
+	p.skip(" \t")
+	if p.done() || p.peek() != '=' {
+		return "", p.errorf("missing \"=\" after key %s", key)
+	}
+	p.advance()
+	p.skip(" \t")
+
+	return key, nil
+}
+
+// readValue reads the value of the given key until the end of the line,
+// or the closing quote for quoted values.
+func (p *parser) readValue(key string) (string, error) {
+	if p.done() {
+		return "", nil
+	}
+
+	var (
+		val string
+		err error
+	)
+
+	switch p.peek() {
+	case '\'':
+		val, err = p.readQuoted(key, '\'', false)
+	case '"':
+		val, err = p.readQuoted(key, '"', true)
+	default:
+		return p.readUnquoted(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// only a comment may follow a quoted value
+	p.skip(" \t")
+	switch {
+	case p.done():
+	case p.peek() == '\n':
+		p.advance()
+	case p.peek() == '#':
+		p.skipLine()
+	default:
+		return "", p.errorf("unexpected character after quoted value of key %s", key)
+	}
+
+	return val, nil
+}
+
+// readUnquoted reads a value until the end of the line or the start
+// of a comment, and trims its surrounding spaces.
+func (p *parser) readUnquoted() string {
+	start := p.pos
+	end := -1
+	for !p.done() && p.peek() != '\n' {
+		if p.peek() == '#' && p.pos > start && isBlank(p.in[p.pos-1]) {
+			end = p.pos
+			p.skipLine()
+			break
+		}
+		p.advance()
+	}
+	if end == -1 {
+		end = p.pos
+		if !p.done() {
+			p.advance() // consume "\n"
+		}
+	}
+	return strings.TrimSpace(p.in[start:end])
+}
+
+// readQuoted reads a value enclosed in quote characters. If escape is true,
+// backslash escape sequences are interpreted.
+func (p *parser) readQuoted(key string, quote byte, escape bool) (string, error) {
+	startLine := p.line
+	p.advance() // opening quote
+
+	var b strings.Builder
+	for !p.done() {
+		c := p.peek()
+		p.advance()
+
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case escape && c == '\\':
+			if p.done() {
+				break
+			}
+			b.WriteByte(unescape(p.peek()))
+			p.advance()
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	p.line = startLine
+	return "", p.errorf("unterminated quoted value of key %s", key)
+}
+
+// unescape returns the character represented by the escape
+// sequence "\" + c.
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	default: // \" \\ \$ and unknown sequences
+		return c
+	}
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.in)
+}
+
+func (p *parser) peek() byte {
+	return p.in[p.pos]
+}
+
+func (p *parser) advance() {
+	if p.in[p.pos] == '\n' {
+		p.line++
+	}
+	p.pos++
+}
+
+// skip advances while the current character is in chars
+// and returns the number of skipped characters.
+func (p *parser) skip(chars string) int {
+	n := 0
+	for !p.done() && strings.IndexByte(chars, p.peek()) != -1 {
+		p.advance()
+		n++
+	}
+	return n
+}
+
+// skipLine advances past the end of the current line.
+func (p *parser) skipLine() {
+	for !p.done() && p.peek() != '\n' {
+		p.advance()
+	}
+	if !p.done() {
+		p.advance()
+	}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return errorutil.WithDetails(ErrSyntax, fmt.Sprintf("line %d", p.line), fmt.Sprintf(format, args...))
+}
+
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '.' || isDigit(c) || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
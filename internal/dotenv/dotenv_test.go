@@ -0,0 +1,164 @@
+package dotenv_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/dotenv"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("parse valid declarations", func(t *testing.T) {
+		in := strings.Join([]string{
+			"# comment",
+			"",
+			"UNQUOTED=hello world  # trailing comment",
+			"export EXPORTED=yes",
+			"  SPACED  =  value  ",
+			"HASH=abc#def",
+			"EMPTY=",
+			`SINGLE='raw \n $value # not a comment'`,
+			`DOUBLE="escaped\n\t\"quote\" \\ \$ # not a comment" # comment`,
+			`MULTI="line 1`,
+			`line 2"`,
+			"dotted.key_0=ok",
+			"OVERRIDE=first",
+			"OVERRIDE=second",
+		}, "\r\n")
+
+		got, err := dotenv.Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := map[string]string{
+			"UNQUOTED":     "hello world",
+			"EXPORTED":     "yes",
+			"SPACED":       "value",
+			"HASH":         "abc#def",
+			"EMPTY":        "",
+			"SINGLE":       `raw \n $value # not a comment`,
+			"DOUBLE":       "escaped\n\t\"quote\" \\ $ # not a comment",
+			"MULTI":        "line 1\nline 2",
+			"dotted.key_0": "ok",
+			"OVERRIDE":     "second",
+		}
+
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("\nexp %q\ngot %q", exp, got)
+		}
+	})
+
+	t.Run("return syntax errors without values", func(t *testing.T) {
+		testcases := []struct {
+			label  string
+			in     string
+			expMsg string
+		}{
+			{
+				label:  "missing equal sign",
+				in:     "A=1\nSECRET s3cr3t",
+				expMsg: `line 2: missing "=" after key SECRET`,
+			},
+			{
+				label:  "invalid key",
+				in:     "1KEY=s3cr3t",
+				expMsg: "line 1: invalid key",
+			},
+			{
+				label:  "unterminated quote",
+				in:     "A=1\nSECRET=\"s3cr3t\n",
+				expMsg: "line 2: unterminated quoted value of key SECRET",
+			},
+			{
+				label:  "characters after quote",
+				in:     "SECRET='s3cr3t'oops",
+				expMsg: "line 1: unexpected character after quoted value of key SECRET",
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.label, func(t *testing.T) {
+				_, err := dotenv.Parse(tc.in)
+				if !errors.Is(err, dotenv.ErrSyntax) {
+					t.Fatalf("exp ErrSyntax, got %v", err)
+				}
+				if !strings.HasSuffix(err.Error(), tc.expMsg) {
+					t.Errorf("\nexp suffix %q\ngot %q", tc.expMsg, err.Error())
+				}
+				if strings.Contains(err.Error(), "s3cr3t") {
+					t.Errorf("error leaks value: %q", err.Error())
+				}
+			})
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("read variables without setting them", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), ".env")
+		content := "DOTENV_TEST_NEW=fromfile\nDOTENV_TEST_SET=fromfile\n"
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("DOTENV_TEST_SET", "fromenv")
+		t.Setenv("DOTENV_TEST_NEW", "")
+		os.Unsetenv("DOTENV_TEST_NEW")
+
+		env, err := dotenv.Load(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := dotenv.Env{"DOTENV_TEST_NEW": "fromfile", "DOTENV_TEST_SET": "fromfile"}
+		if !reflect.DeepEqual(env, exp) {
+			t.Errorf("variables:\nexp %v\ngot %v", exp, env)
+		}
+		if _, ok := os.LookupEnv("DOTENV_TEST_NEW"); ok {
+			t.Error("DOTENV_TEST_NEW: exp unset in the process environment")
+		}
+	})
+
+	t.Run("lookup variables of the environment first", func(t *testing.T) {
+		t.Setenv("DOTENV_TEST_SET", "fromenv")
+		env := dotenv.Env{"DOTENV_TEST_SET": "fromfile", "DOTENV_TEST_FILE": "fromfile"}
+
+		if got, _ := env.Lookup("DOTENV_TEST_SET"); got != "fromenv" {
+			t.Errorf("DOTENV_TEST_SET: exp fromenv, got %s", got)
+		}
+		if got, ok := env.Lookup("DOTENV_TEST_FILE"); !ok || got != "fromfile" {
+			t.Errorf("DOTENV_TEST_FILE: exp fromfile, got %s", got)
+		}
+		if _, ok := env.Lookup("DOTENV_TEST_NONE"); ok {
+			t.Error("DOTENV_TEST_NONE: exp not found")
+		}
+	})
+
+	t.Run("return the values of credentials only", func(t *testing.T) {
+		env := dotenv.Env{
+			"API_TOKEN":   "t0k3n",
+			"DB_Password": "p4ss",
+			"X_API_KEY":   "k3y",
+			"BASE_URL":    "http://localhost:8080",
+			"METHOD":      "POST",
+			"REQUESTS":    "1000",
+		}
+
+		exp := []string{"k3y", "p4ss", "t0k3n"}
+		if got := env.Secrets(); !reflect.DeepEqual(got, exp) {
+			t.Errorf("exp %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("return ErrFileNotFound", func(t *testing.T) {
+		_, err := dotenv.Load(filepath.Join(t.TempDir(), "nope.env"))
+		if !errors.Is(err, dotenv.ErrFileNotFound) {
+			t.Errorf("exp ErrFileNotFound, got %v", err)
+		}
+	})
+}
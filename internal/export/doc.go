@@ -0,0 +1,7 @@
+// Package export converts benchmark configs into the inputs of other
+// load testing tools, so that a single config file can drive several
+// tools.
+//
+// Exporters never fail on options they cannot represent: they return
+// warnings describing what was left out instead.
+package export
@@ -0,0 +1,136 @@
+package export_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/export"
+)
+
+func TestK6(t *testing.T) {
+	cfg := newConfig()
+
+	script, warnings := export.K6(cfg)
+	if len(warnings) != 1 {
+		t.Errorf("exp 1 warning for the StdDev test, got %v", warnings)
+	}
+
+	for _, exp := range []string{
+		`"executor": "shared-iterations"`,
+		`"iterations": 100`,
+		`"vus": 4`,
+		`"maxDuration": "1m0s"`,
+		`"avg<=150"`,
+		`"rate==0"`,
+		`const method = "POST";`,
+		`const url = "https://example.com/users?page=2";`,
+		`const body = "{\"name\":\"a&b\"}";`,
+		`"X-Api-Key": "abc"`,
+		`"timeout": "2s"`,
+		`sleep(0.5);`,
+	} {
+		if !strings.Contains(string(script), exp) {
+			t.Errorf("missing %s in script:\n%s", exp, script)
+		}
+	}
+
+	t.Run("unlimited requests run for the global timeout", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Runner.Requests = -1
+
+		script, _ := export.K6(cfg)
+		for _, exp := range []string{`"executor": "constant-vus"`, `"duration": "1m0s"`} {
+			if !strings.Contains(string(script), exp) {
+				t.Errorf("missing %s in script:\n%s", exp, script)
+			}
+		}
+	})
+}
+
+func TestVegeta(t *testing.T) {
+	t.Run("rate from interval", func(t *testing.T) {
+		cfg := newConfig()
+
+		targets, flags, warnings := export.Vegeta(cfg, "targets.json")
+		if len(warnings) != 1 {
+			t.Errorf("exp 1 warning for the tests, got %v", warnings)
+		}
+
+		var target struct {
+			Method string
+			URL    string
+			Body   []byte
+			Header http.Header
+		}
+		if err := json.Unmarshal(targets, &target); err != nil {
+			t.Fatal(err)
+		}
+		if target.Method != "POST" || target.URL != "https://example.com/users?page=2" ||
+			string(target.Body) != `{"name":"a&b"}` || target.Header.Get("X-Api-Key") != "abc" {
+			t.Errorf("bad target: %+v", target)
+		}
+
+		expFlags := []string{
+			"-format=json",
+			"-targets=targets.json",
+			"-workers=4",
+			"-max-workers=4",
+			"-timeout=2s",
+			"-rate=4/500ms",
+			"-duration=12.5s",
+		}
+		if !reflect.DeepEqual(flags, expFlags) {
+			t.Errorf("bad flags:\nexp %v\ngot %v", expFlags, flags)
+		}
+	})
+
+	t.Run("max rate without interval", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Runner.Interval = 0
+		cfg.Tests = nil
+
+		_, flags, warnings := export.Vegeta(cfg, "targets.json")
+		if len(warnings) != 1 {
+			t.Errorf("exp 1 warning for the requests count, got %v", warnings)
+		}
+		if got := flags[len(flags)-2:]; !reflect.DeepEqual(got, []string{"-rate=0", "-duration=1m0s"}) {
+			t.Errorf("bad flags: %v", got)
+		}
+	})
+
+	t.Run("targets from stdin without targets file", func(t *testing.T) {
+		_, flags, _ := export.Vegeta(newConfig(), "")
+		for _, flag := range flags {
+			if strings.HasPrefix(flag, "-targets") {
+				t.Errorf("unexpected flag %s", flag)
+			}
+		}
+	})
+}
+
+func newConfig() runner.Config {
+	u, _ := url.Parse("https://example.com/users?page=2")
+	cfg := runner.Config{}
+	cfg.Request.Method = "POST"
+	cfg.Request.URL = u
+	cfg.Request.Header = http.Header{"X-Api-Key": {"abc"}}
+	cfg.Request.Body = runner.NewRequestBody("raw", `{"name":"a&b"}`)
+	cfg.Runner.Requests = 100
+	cfg.Runner.Concurrency = 4
+	cfg.Runner.Interval = 500 * time.Millisecond
+	cfg.Runner.RequestTimeout = 2 * time.Second
+	cfg.Runner.GlobalTimeout = time.Minute
+	cfg.Tests = []runner.TestCase{
+		{Name: "mean", Field: "ResponseTimes.Mean", Predicate: "LTE", Target: 150 * time.Millisecond},
+		{Name: "no failure", Field: "RequestFailureCount", Predicate: "EQ", Target: 0},
+		{Name: "stddev", Field: "ResponseTimes.StdDev", Predicate: "LT", Target: 10 * time.Millisecond},
+	}
+	return cfg
+}
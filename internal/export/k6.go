@@ -0,0 +1,147 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// k6Predicates maps the predicates to the operators of k6 thresholds.
+var k6Predicates = map[runner.TestPredicate]string{
+	"EQ":  "==",
+	"NEQ": "!=",
+	"GT":  ">",
+	"GTE": ">=",
+	"LT":  "<",
+	"LTE": "<=",
+}
+
+// k6TimeStats maps the fields of the response times statistics
+// to the aggregations of the k6 metric http_req_duration.
+var k6TimeStats = map[string]string{
+	"Min":    "min",
+	"Max":    "max",
+	"Mean":   "avg",
+	"Median": "med",
+}
+
+// K6 returns a k6 script equivalent to cfg. The tests of cfg are
+// converted to thresholds when possible. It returns warnings about
+// the options that cannot be converted.
+func K6(cfg runner.Config) (script []byte, warnings []string) {
+	scenario := map[string]interface{}{"vus": cfg.Runner.Concurrency}
+	if cfg.Runner.Requests > 0 {
+		scenario["executor"] = "shared-iterations"
+		scenario["iterations"] = cfg.Runner.Requests
+		scenario["maxDuration"] = cfg.Runner.GlobalTimeout.String()
+	} else {
+		scenario["executor"] = "constant-vus"
+		scenario["duration"] = cfg.Runner.GlobalTimeout.String()
+	}
+
+	thresholds := map[string][]string{}
+	for _, test := range cfg.Tests {
+		metric, expr, ok := k6Threshold(test)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("test %q cannot be converted to a k6 threshold: ignored", test.Name))
+			continue
+		}
+		thresholds[metric] = append(thresholds[metric], expr)
+	}
+
+	options := map[string]interface{}{
+		"scenarios": map[string]interface{}{"benchttp": scenario},
+	}
+	if len(thresholds) != 0 {
+		options["thresholds"] = thresholds
+	}
+
+	params := map[string]interface{}{}
+	if len(cfg.Request.Header) != 0 {
+		header := map[string]string{}
+		for key, values := range cfg.Request.Header {
+			header[key] = strings.Join(values, ", ")
+		}
+		params["headers"] = header
+	}
+	if cfg.Runner.RequestTimeout > 0 {
+		params["timeout"] = cfg.Runner.RequestTimeout.String()
+	}
+
+	var body interface{}
+	if len(cfg.Request.Body.Content) != 0 {
+		body = string(cfg.Request.Body.Content)
+	}
+
+	var b strings.Builder
+	b.WriteString("// Generated by benchttp export k6.\n")
+	b.WriteString("import http from 'k6/http';\n")
+	if cfg.Runner.Interval > 0 {
+		b.WriteString("import { sleep } from 'k6';\n")
+	}
+	fmt.Fprintf(&b, "\nexport const options = %s;\n\n", jsValue(options))
+	fmt.Fprintf(&b, "const method = %s;\n", jsValue(cfg.Request.Method))
+	fmt.Fprintf(&b, "const url = %s;\n", jsValue(cfg.Request.URL.String()))
+	fmt.Fprintf(&b, "const body = %s;\n", jsValue(body))
+	fmt.Fprintf(&b, "const params = %s;\n\n", jsValue(params))
+	b.WriteString("export default function () {\n")
+	b.WriteString("  http.request(method, url, body, params);\n")
+	if cfg.Runner.Interval > 0 {
+		fmt.Fprintf(&b, "  sleep(%s);\n", formatFloat(cfg.Runner.Interval.Seconds()))
+	}
+	b.WriteString("}\n")
+
+	return []byte(b.String()), warnings
+}
+
+// k6Threshold returns the k6 metric and threshold expression equivalent
+// to test, or false if there is none.
+func k6Threshold(test runner.TestCase) (metric, expr string, ok bool) {
+	op, ok := k6Predicates[test.Predicate]
+	if !ok {
+		return "", "", false
+	}
+
+	field := string(test.Field)
+	switch {
+	case strings.HasPrefix(field, "ResponseTimes."):
+		agg, ok := k6TimeStats[strings.TrimPrefix(field, "ResponseTimes.")]
+		target, isDuration := test.Target.(time.Duration)
+		if !ok || !isDuration {
+			return "", "", false
+		}
+		return "http_req_duration", agg + op + formatFloat(float64(target)/float64(time.Millisecond)), true
+	case field == "RequestCount":
+		target, ok := test.Target.(int)
+		if !ok {
+			return "", "", false
+		}
+		return "http_reqs", "count" + op + strconv.Itoa(target), true
+	case field == "RequestFailureCount":
+		// k6 only exposes the rate of failed requests
+		if target, ok := test.Target.(int); ok && target == 0 {
+			if test.Predicate == "EQ" || test.Predicate == "LTE" {
+				return "http_req_failed", "rate==0", true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// jsValue returns v as a JavaScript literal.
+func jsValue(v interface{}) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	enc.Encode(v) //nolint:errcheck // values are always encodable
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// vegetaTarget is a target of the vegeta JSON targets format.
+type vegetaTarget struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Body   []byte              `json:"body,omitempty"`
+	Header map[string][]string `json:"header,omitempty"`
+}
+
+// Vegeta returns a vegeta targets file in JSON format equivalent to
+// the request of cfg, and the flags of "vegeta attack" equivalent to
+// its runner options, given the path of the targets file, or an empty
+// path if the targets are read from the standard input.
+// It returns warnings about the options that cannot be converted.
+func Vegeta(cfg runner.Config, targetsFile string) (targets []byte, flags, warnings []string) {
+	target := vegetaTarget{
+		Method: cfg.Request.Method,
+		URL:    cfg.Request.URL.String(),
+		Body:   cfg.Request.Body.Content,
+		Header: cfg.Request.Header,
+	}
+	targets, _ = json.Marshal(target) // target is always encodable
+	targets = append(targets, '\n')
+
+	concurrency := cfg.Runner.Concurrency
+	flags = []string{"-format=json"}
+	if targetsFile != "" {
+		flags = append(flags, "-targets="+targetsFile)
+	}
+	flags = append(flags,
+		fmt.Sprintf("-workers=%d", concurrency),
+		fmt.Sprintf("-max-workers=%d", concurrency),
+	)
+
+	if cfg.Runner.RequestTimeout > 0 {
+		flags = append(flags, "-timeout="+cfg.Runner.RequestTimeout.String())
+	}
+
+	duration := cfg.Runner.GlobalTimeout
+	if interval := cfg.Runner.Interval; interval > 0 {
+		// each worker sends a request per interval
+		flags = append(flags, fmt.Sprintf("-rate=%d/%s", concurrency, interval))
+		if requests := cfg.Runner.Requests; requests > 0 {
+			rounds := (requests + concurrency - 1) / concurrency
+			if d := time.Duration(rounds) * interval; d < duration {
+				duration = d
+			}
+		}
+	} else {
+		flags = append(flags, "-rate=0")
+		if cfg.Runner.Requests > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"vegeta cannot limit the number of requests (%d): the attack lasts %s",
+				cfg.Runner.Requests, duration,
+			))
+		}
+	}
+	flags = append(flags, "-duration="+duration.String())
+
+	if len(cfg.Tests) != 0 {
+		warnings = append(warnings, "vegeta has no equivalent of tests: ignored")
+	}
+
+	return targets, flags, warnings
+}
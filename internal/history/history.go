@@ -0,0 +1,259 @@
+// Package history persists the reports of benchmarks in a local store,
+// so that their metrics can be followed over time.
+//
+// Each entry of the store is a JSON file of the store directory, named
+// after the time of the run, the hash of the benchmarked config and the
+// git commit it was run at.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/errorutil"
+	"github.com/benchttp/cli/internal/reportfile"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+// DefaultDir is the directory of the history store, relative
+// to the working directory.
+const DefaultDir = ".benchttp/history"
+
+var (
+	// ErrNotFound signals an entry not found in the store.
+	ErrNotFound = errors.New("history entry not found")
+
+	// ErrAmbiguous signals an id prefix matching several entries.
+	ErrAmbiguous = errors.New("ambiguous history entry")
+
+	// ErrFileRead signals an error reading an entry of the store.
+	ErrFileRead = errors.New("invalid history entry")
+
+	// ErrFileWrite signals an error writing an entry to the store.
+	ErrFileWrite = errors.New("cannot write history entry")
+)
+
+// timeLayout is the layout of the time in the ids of the entries,
+// that sorts them chronologically.
+const timeLayout = "20060102T150405.000Z"
+
+// Entry is a report of the history store.
+type Entry struct {
+	// ID identifies the entry in the store.
+	ID string `json:"-"`
+
+	// Name is the name of the benchmark, e.g. its config file.
+	Name string `json:"name"`
+
+	// ConfigHash identifies the benchmarked config, see ConfigHash.
+	ConfigHash string `json:"configHash"`
+
+	// Commit is the git commit of the working directory at the time
+	// of the run, empty outside of a git repository.
+	Commit string `json:"commit"`
+
+	// Time is the time of the run.
+	Time time.Time `json:"time"`
+
+	Report *runner.Report `json:"-"`
+
+	// Warnings are the names of the test cases of the report
+	// of severity warn.
+	Warnings testsuite.Warnings `json:"-"`
+}
+
+// ShortCommit returns the abbreviated form of the commit of the entry.
+func (e Entry) ShortCommit() string {
+	if len(e.Commit) > 7 {
+		return e.Commit[:7]
+	}
+	return e.Commit
+}
+
+// entryFile is the representation of an entry in its file.
+type entryFile struct {
+	Entry
+	Report json.RawMessage `json:"report"`
+}
+
+// ConfigHash returns a short hash identifying the request and runner
+// options of cfg. Its tests are ignored: changing them does not change
+// the benchmark.
+func ConfigHash(cfg runner.Config) string {
+	u := ""
+	if cfg.Request.URL != nil {
+		u = cfg.Request.URL.String()
+	}
+	b, _ := json.Marshal(struct {
+		Method string
+		URL    string
+		Header map[string][]string
+		Body   runner.RequestBody
+		Runner runner.RecorderConfig
+	}{cfg.Request.Method, u, cfg.Request.Header, cfg.Request.Body, cfg.Runner})
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// GitCommit returns the commit checked out in the working directory,
+// or an empty string if it is not in a git repository.
+func GitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Save writes entry to the store of directory dir, creating it if
+// needed, and returns it with its id set.
+func Save(dir string, entry Entry) (Entry, error) {
+	rep, err := reportfile.Marshal(entry.Report, entry.Warnings)
+	if err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileWrite, err)
+	}
+	b, err := json.MarshalIndent(entryFile{Entry: entry, Report: rep}, "", "  ")
+	if err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileWrite, err)
+	}
+
+	commit := entry.ShortCommit()
+	if commit == "" {
+		commit = "nogit"
+	}
+	entry.ID = strings.Join([]string{entry.Time.UTC().Format(timeLayout), entry.ConfigHash, commit}, "_")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileWrite, dir, err)
+	}
+	filename := filepath.Join(dir, entry.ID+".json")
+	if err := os.WriteFile(filename, append(b, '\n'), 0o600); err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileWrite, filename, err)
+	}
+	return entry, nil
+}
+
+// List returns the entries of the store of directory dir, from the
+// oldest to the most recent. A missing store has no entries.
+func List(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, errorutil.WithDetails(ErrFileRead, dir, err)
+	}
+
+	entries := []Entry{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		entry, err := read(dir, strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// Find returns the entry of the store of directory dir whose id starts
+// with prefix, or the most recent one if prefix is "latest".
+func Find(dir, prefix string) (Entry, error) {
+	entries, err := List(dir)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if prefix == "latest" {
+		if len(entries) == 0 {
+			return Entry{}, errorutil.WithDetails(ErrNotFound, prefix)
+		}
+		return entries[len(entries)-1], nil
+	}
+
+	matches := []Entry{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ID, prefix) {
+			matches = append(matches, entry)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Entry{}, errorutil.WithDetails(ErrNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return Entry{}, errorutil.WithDetails(ErrAmbiguous, prefix, "matches several entries")
+	}
+}
+
+// Prune removes from the store of directory dir the entries older than
+// before, if not zero, and the entries beyond the keep most recent ones
+// of each config, if keep is positive. It returns the removed entries.
+func Prune(dir string, keep int, before time.Time) ([]Entry, error) {
+	entries, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := []Entry{}
+	kept := map[string]int{}
+	// iterate from the most recent to count the kept entries per config
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		tooOld := !before.IsZero() && entry.Time.Before(before)
+		tooMany := keep > 0 && kept[entry.ConfigHash] >= keep
+		if !tooOld && !tooMany {
+			kept[entry.ConfigHash]++
+			continue
+		}
+
+		filename := filepath.Join(dir, entry.ID+".json")
+		if err := os.Remove(filename); err != nil {
+			return removed, errorutil.WithDetails(ErrFileWrite, filename, err)
+		}
+		removed = append(removed, entry)
+	}
+	return removed, nil
+}
+
+// read reads the entry of the given id from the store of directory dir.
+func read(dir, id string) (Entry, error) {
+	filename := filepath.Join(dir, id+".json")
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+
+	var f entryFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+	rep, warnings, err := reportfile.Unmarshal(f.Report)
+	if err != nil {
+		return Entry{}, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+
+	entry := f.Entry
+	entry.ID = id
+	entry.Report = rep
+	entry.Warnings = warnings
+	return entry, nil
+}
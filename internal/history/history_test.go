@@ -0,0 +1,158 @@
+package history_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/history"
+)
+
+func TestSaveList(t *testing.T) {
+	dir := t.TempDir() + "/history"
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	for i, hash := range []string{"aaa", "bbb", "aaa"} {
+		rep := &runner.Report{}
+		rep.Metadata.Config.Request = rep.Metadata.Config.Request.WithURL("https://example.com")
+		rep.Metadata.TotalDuration = time.Duration(i+1) * time.Second
+
+		entry, err := history.Save(dir, history.Entry{
+			Name:       "bench.yml",
+			ConfigHash: hash,
+			Commit:     "0123456789abcdef",
+			Time:       t0.Add(time.Duration(i) * time.Hour),
+			Report:     rep,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := t0.Add(time.Duration(i)*time.Hour).Format("20060102T150405.000Z") + "_" + hash + "_0123456"; entry.ID != exp {
+			t.Errorf("exp id %s, got %s", exp, entry.ID)
+		}
+	}
+
+	entries, err := history.List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("exp 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Report.Metadata.TotalDuration != time.Duration(i+1)*time.Second {
+			t.Errorf("exp entries sorted from the oldest, got %v at %d", entry.Report.Metadata.TotalDuration, i)
+		}
+		if entry.Name != "bench.yml" || entry.Commit != "0123456789abcdef" || entry.ShortCommit() != "0123456" {
+			t.Errorf("bad entry: %+v", entry)
+		}
+		if entry.Report.Metadata.Config.Request.URL.String() != "https://example.com" {
+			t.Errorf("exp report url to be restored, got %v", entry.Report.Metadata.Config.Request.URL)
+		}
+	}
+
+	if entries, err := history.List(t.TempDir() + "/missing"); err != nil || len(entries) != 0 {
+		t.Errorf("exp no entries for a missing store, got %v, %v", entries, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	save(t, dir, "aaa", t0)
+	save(t, dir, "bbb", t0.Add(time.Minute))
+
+	if entry, err := history.Find(dir, "latest"); err != nil || entry.ConfigHash != "bbb" {
+		t.Errorf("latest: exp bbb, got %+v, %v", entry, err)
+	}
+	if entry, err := history.Find(dir, "20261019T1200"); err != nil || entry.ConfigHash != "aaa" {
+		t.Errorf("prefix: exp aaa, got %+v, %v", entry, err)
+	}
+	if _, err := history.Find(dir, "2026"); !errors.Is(err, history.ErrAmbiguous) {
+		t.Errorf("exp ErrAmbiguous, got %v", err)
+	}
+	if _, err := history.Find(dir, "1999"); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("exp ErrNotFound, got %v", err)
+	}
+	if _, err := history.Find(t.TempDir(), "latest"); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("exp ErrNotFound for an empty store, got %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for i, hash := range []string{"aaa", "aaa", "bbb", "aaa", "bbb"} {
+			save(t, dir, hash, t0.Add(time.Duration(i)*time.Hour))
+		}
+		return dir
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		dir := setup(t)
+		removed, err := history.Prune(dir, 1, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removed) != 3 {
+			t.Errorf("exp 3 removed entries, got %d", len(removed))
+		}
+		checkRemaining(t, dir, t0.Add(3*time.Hour), t0.Add(4*time.Hour))
+	})
+
+	t.Run("before", func(t *testing.T) {
+		dir := setup(t)
+		if _, err := history.Prune(dir, 0, t0.Add(2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		checkRemaining(t, dir, t0.Add(2*time.Hour), t0.Add(3*time.Hour), t0.Add(4*time.Hour))
+	})
+}
+
+func save(t *testing.T, dir, hash string, at time.Time) {
+	t.Helper()
+	if _, err := history.Save(dir, history.Entry{ConfigHash: hash, Time: at, Report: &runner.Report{}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkRemaining(t *testing.T, dir string, times ...time.Time) {
+	t.Helper()
+	entries, err := history.List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(times) {
+		t.Fatalf("exp %d remaining entries, got %d", len(times), len(entries))
+	}
+	for i, entry := range entries {
+		if !entry.Time.Equal(times[i]) {
+			t.Errorf("exp entry at %v, got %v", times[i], entry.Time)
+		}
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	cfg := runner.Config{}
+	cfg.Request = cfg.Request.WithURL("https://example.com")
+	cfg.Runner.Requests = 10
+
+	withTests := cfg
+	withTests.Tests = []runner.TestCase{{Name: "test"}}
+	if history.ConfigHash(cfg) != history.ConfigHash(withTests) {
+		t.Error("exp tests to be ignored")
+	}
+
+	other := cfg
+	other.Runner.Requests = 20
+	if history.ConfigHash(cfg) == history.ConfigHash(other) {
+		t.Error("exp different hashes for different runner options")
+	}
+
+	if n := len(history.ConfigHash(cfg)); n != 12 {
+		t.Errorf("exp a 12 characters hash, got %d", n)
+	}
+}
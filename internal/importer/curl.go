@@ -0,0 +1,368 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// ErrCurl signals a curl command line that cannot be converted.
+var ErrCurl = errors.New("invalid curl command")
+
+// curlValueOptions lists the curl options expecting a value, including
+// the ones that are not supported, so their value is not mistaken for
+// the url.
+var curlValueOptions = map[string]bool{
+	"-H": true, "--header": true,
+	"-X": true, "--request": true,
+	"-d": true, "--data": true, "--data-ascii": true, "--data-binary": true,
+	"--data-raw": true, "--data-urlencode": true, "--json": true,
+	"-u": true, "--user": true, "--oauth2-bearer": true,
+	"-A": true, "--user-agent": true,
+	"-e": true, "--referer": true,
+	"-b": true, "--cookie": true,
+	"-r": true, "--range": true,
+	"-m": true, "--max-time": true,
+	"--url": true,
+	// ignored, see curlIgnoredOptions
+	"-o": true, "--output": true,
+	"-w": true, "--write-out": true,
+	"-D": true, "--dump-header": true,
+	"--trace": true, "--trace-ascii": true,
+	// unsupported
+	"-c": true, "--cookie-jar": true,
+	"-x": true, "--proxy": true,
+	"-U": true, "--proxy-user": true,
+	"-F": true, "--form": true,
+	"-T": true, "--upload-file": true,
+	"-E": true, "--cert": true, "--key": true, "--cacert": true, "--capath": true,
+	"-K": true, "--config": true,
+	"--connect-timeout": true, "--max-redirs": true, "--retry": true,
+	"--limit-rate": true, "--resolve": true, "--connect-to": true,
+	"--interface": true,
+}
+
+// curlIgnoredOptions lists the curl options that do not affect the request
+// or that are the default behavior of benchttp, and are silently ignored.
+var curlIgnoredOptions = map[string]bool{
+	"--compressed": true,
+	"-s":           true, "--silent": true,
+	"-S": true, "--show-error": true,
+	"-L": true, "--location": true,
+	"-v": true, "--verbose": true,
+	"-i": true, "--include": true,
+	"-#": true, "--progress-bar": true, "--no-progress-meter": true,
+	"-g": true, "--globoff": true,
+	"-f": true, "--fail": true,
+	"-o": true, "--output": true,
+	"-w": true, "--write-out": true,
+	"-D": true, "--dump-header": true,
+	"--trace": true, "--trace-ascii": true,
+}
+
+// Environment variables referenced by the imported credentials,
+// that are not written to the config file.
+const (
+	curlPasswordEnv = "BENCHTTP_PASSWORD"
+	curlTokenEnv    = "BENCHTTP_TOKEN"
+)
+
+// curlRequest is the request described by a curl command.
+type curlRequest struct {
+	url      string
+	method   string
+	header   map[string][]string
+	data     []string
+	getData  bool
+	auth     *configfile.AuthRepresentation
+	timeout  string
+	warnings []string
+}
+
+// CurlCommand converts a curl command line into the representation
+// of an equivalent config file. See Curl.
+func CurlCommand(line string) (configfile.Representation, []string, error) {
+	args, err := splitWords(line)
+	if err != nil {
+		return configfile.Representation{}, nil, err
+	}
+	return Curl(args)
+}
+
+// Curl converts the words of a curl command line, optionally starting
+// with "curl", into the representation of an equivalent config file.
+// It returns warnings about the options that cannot be represented.
+func Curl(args []string) (configfile.Representation, []string, error) {
+	if len(args) != 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+
+	req := &curlRequest{header: map[string][]string{}}
+	if err := req.parse(expandShortOptions(args)); err != nil {
+		return configfile.Representation{}, req.warnings, err
+	}
+
+	repr, err := req.representation()
+	return repr, req.warnings, err
+}
+
+// expandShortOptions splits clusters of short options ("-sSL") and short
+// options attached to their value ("-XPOST") into separate words.
+func expandShortOptions(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) <= 2 || arg[0] != '-' || arg[1] == '-' {
+			expanded = append(expanded, arg)
+			continue
+		}
+		for j := 1; j < len(arg); j++ {
+			opt := "-" + string(arg[j])
+			expanded = append(expanded, opt)
+			if curlValueOptions[opt] && j+1 < len(arg) {
+				expanded = append(expanded, arg[j+1:])
+				break
+			}
+		}
+	}
+	return expanded
+}
+
+// parse reads the curl options from args into req.
+func (req *curlRequest) parse(args []string) error { //nolint:gocognit // flat options switch
+	for i := 0; i < len(args); i++ {
+		opt := args[i]
+
+		if !strings.HasPrefix(opt, "-") || opt == "-" {
+			req.setURL(opt)
+			continue
+		}
+
+		var val string
+		if curlValueOptions[opt] {
+			if i+1 >= len(args) {
+				return errorutil.WithDetails(ErrCurl, fmt.Sprintf("missing value for option %s", opt))
+			}
+			i++
+			val = args[i]
+		}
+
+		switch opt {
+		case "--url":
+			req.setURL(val)
+		case "-H", "--header":
+			req.addHeader(val)
+		case "-X", "--request":
+			req.method = val
+		case "-I", "--head":
+			req.method = "HEAD"
+		case "-G", "--get":
+			req.getData = true
+		case "-d", "--data", "--data-ascii":
+			if err := req.addData(val, true); err != nil {
+				return err
+			}
+		case "--data-binary":
+			if err := req.addData(val, false); err != nil {
+				return err
+			}
+		case "--data-raw":
+			req.data = append(req.data, val)
+		case "--data-urlencode":
+			req.data = append(req.data, urlencodeData(val))
+		case "--json":
+			req.data = append(req.data, val)
+			req.setDefaultHeader("Content-Type", "application/json")
+			req.setDefaultHeader("Accept", "application/json")
+		case "-u", "--user":
+			req.setBasicAuth(val)
+		case "--oauth2-bearer":
+			req.setBearerAuth()
+		case "-A", "--user-agent":
+			req.header["User-Agent"] = []string{val}
+		case "-e", "--referer":
+			req.header["Referer"] = []string{val}
+		case "-b", "--cookie":
+			req.setCookie(val)
+		case "-r", "--range":
+			req.header["Range"] = []string{"bytes=" + val}
+		case "-m", "--max-time":
+			if err := req.setTimeout(val); err != nil {
+				return err
+			}
+		case "-k", "--insecure":
+			req.warnf("option %s cannot be set in a config file: use flag -insecure of command run", opt)
+		default:
+			if !curlIgnoredOptions[opt] {
+				req.warnf("unsupported option %s: ignored", opt)
+			}
+		}
+	}
+
+	if req.url == "" {
+		return errorutil.WithDetails(ErrCurl, "no url specified")
+	}
+	return nil
+}
+
+func (req *curlRequest) warnf(format string, args ...interface{}) {
+	req.warnings = append(req.warnings, fmt.Sprintf(format, args...))
+}
+
+func (req *curlRequest) setURL(rawURL string) {
+	if req.url != "" {
+		req.warnf("multiple urls: only the first one is kept (%s)", req.url)
+		return
+	}
+	// like curl, default to http when the scheme is omitted
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	req.url = rawURL
+}
+
+// addHeader adds a header in curl format "Name: value". Curl special
+// syntaxes "Name:" (remove a default header) and "Name;" (empty value)
+// are not supported.
+func (req *curlRequest) addHeader(raw string) {
+	keyval := strings.SplitN(raw, ":", 2)
+	if len(keyval) != 2 || strings.TrimSpace(keyval[1]) == "" {
+		req.warnf("unsupported header %q: ignored", raw)
+		return
+	}
+	key, val := strings.TrimSpace(keyval[0]), strings.TrimSpace(keyval[1])
+	req.header[key] = append(req.header[key], val)
+}
+
+// setDefaultHeader sets the header key to val unless it is already set,
+// regardless of the case of the key.
+func (req *curlRequest) setDefaultHeader(key, val string) {
+	for k := range req.header {
+		if strings.EqualFold(k, key) {
+			return
+		}
+	}
+	req.header[key] = []string{val}
+}
+
+// addData adds a data part. If it starts with "@", it is read from
+// the file it references, with newlines removed if strip is true.
+func (req *curlRequest) addData(raw string, strip bool) error {
+	if !strings.HasPrefix(raw, "@") {
+		req.data = append(req.data, raw)
+		return nil
+	}
+
+	b, err := os.ReadFile(strings.TrimPrefix(raw, "@"))
+	if err != nil {
+		return errorutil.WithDetails(ErrCurl, "cannot read data file", err)
+	}
+	data := string(b)
+	if strip {
+		data = strings.NewReplacer("\r", "", "\n", "").Replace(data)
+	}
+	req.data = append(req.data, data)
+	return nil
+}
+
+// urlencodeData encodes a data part the way curl --data-urlencode does
+// for the formats "content" and "name=content".
+func urlencodeData(raw string) string {
+	if i := strings.IndexByte(raw, '='); i != -1 {
+		return raw[:i+1] + url.QueryEscape(raw[i+1:])
+	}
+	return url.QueryEscape(raw)
+}
+
+// setBasicAuth sets basic credentials in curl format "user:password".
+// The password is not written to the config file: it is read from
+// environment variable curlPasswordEnv instead.
+func (req *curlRequest) setBasicAuth(raw string) {
+	user := strings.SplitN(raw, ":", 2)[0]
+	req.auth = &configfile.AuthRepresentation{
+		Type:     "basic",
+		Username: escapeEnv(user),
+		Password: "env:" + curlPasswordEnv,
+	}
+	req.warnf("the password of user %s is read from environment variable %s: set it before running", user, curlPasswordEnv)
+}
+
+// setBearerAuth sets bearer credentials. Like the password of basic
+// credentials, the token is read from environment variable curlTokenEnv.
+func (req *curlRequest) setBearerAuth() {
+	req.auth = &configfile.AuthRepresentation{Type: "bearer", Token: "env:" + curlTokenEnv}
+	req.warnf("the bearer token is read from environment variable %s: set it before running", curlTokenEnv)
+}
+
+// setCookie sets the Cookie header if raw is cookie data. Curl also
+// accepts a file name, that is not supported.
+func (req *curlRequest) setCookie(raw string) {
+	if !strings.Contains(raw, "=") {
+		req.warnf("unsupported cookie file %s: ignored", raw)
+		return
+	}
+	req.header["Cookie"] = append(req.header["Cookie"], raw)
+}
+
+// setTimeout sets the request timeout from a number of seconds.
+func (req *curlRequest) setTimeout(raw string) error {
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return errorutil.WithDetails(ErrCurl, fmt.Sprintf("invalid max time %q", raw))
+	}
+	req.timeout = time.Duration(seconds * float64(time.Second)).String()
+	return nil
+}
+
+// representation returns the representation of the config file
+// equivalent to req.
+func (req *curlRequest) representation() (configfile.Representation, error) {
+	var repr configfile.Representation
+
+	rawURL, method, body := req.url, req.method, strings.Join(req.data, "&")
+
+	switch {
+	case req.getData && body != "":
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return repr, errorutil.WithDetails(ErrCurl, err)
+		}
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+		u.RawQuery += body
+		rawURL, body = u.String(), ""
+		if method == "" {
+			method = "GET"
+		}
+	case method == "" && len(req.data) != 0:
+		method = "POST"
+	case method == "":
+		method = "GET"
+	}
+
+	rawURL = escapeEnv(rawURL)
+	repr.Request.URL = &rawURL
+	repr.Request.Method = &method
+	if len(req.header) != 0 {
+		var warnings []string
+		repr.Request.Header, warnings = secretHeaderEnv(escapeEnvHeader(req.header))
+		req.warnings = append(req.warnings, warnings...)
+	}
+	if body != "" {
+		repr.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: escapeEnv(body)}
+	}
+	repr.Request.Auth = req.auth
+	if req.timeout != "" {
+		repr.Runner.RequestTimeout = &req.timeout
+	}
+
+	return repr, nil
+}
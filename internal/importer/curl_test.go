@@ -0,0 +1,229 @@
+package importer_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/importer"
+)
+
+func TestCurlCommand(t *testing.T) {
+	t.Run("convert devtools command", func(t *testing.T) {
+		cmd := `curl 'https://api.example.com/users?page=2' \
+  -H 'accept: application/json' \
+  -H $'x-note: it\'s\tok' \
+  -H "authorization: Bearer \"abc\"" \
+  --data-raw '{"name":"a b"}' \
+  --compressed -sSL -m 2.5`
+
+		repr, warnings, err := importer.CurlCommand(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "BENCHTTP_AUTHORIZATION") {
+			t.Errorf("exp warning about BENCHTTP_AUTHORIZATION, got %v", warnings)
+		}
+
+		exp := configfile.Representation{}
+		exp.Request.URL = ptr("https://api.example.com/users?page=2")
+		exp.Request.Method = ptr("POST")
+		exp.Request.Header = map[string][]string{
+			"accept":        {"application/json"},
+			"x-note":        {"it's\tok"},
+			"authorization": {"${BENCHTTP_AUTHORIZATION}"},
+		}
+		exp.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: `{"name":"a b"}`}
+		exp.Runner.RequestTimeout = ptr("2.5s")
+
+		if !reflect.DeepEqual(repr, exp) {
+			t.Errorf("\nexp %#v\ngot %#v", exp, repr)
+		}
+	})
+
+	t.Run("convert data, method and auth options", func(t *testing.T) {
+		testcases := []struct {
+			label     string
+			cmd       string
+			expURL    string
+			expMethod string
+			expBody   string
+			expAuth   *configfile.AuthRepresentation
+		}{
+			{
+				label:     "default scheme and method",
+				cmd:       "curl example.com",
+				expURL:    "http://example.com",
+				expMethod: "GET",
+			},
+			{
+				label:     "joined data and explicit method",
+				cmd:       "curl -XPUT http://a.b -d a=1 --data-urlencode 'b=x y'",
+				expURL:    "http://a.b",
+				expMethod: "PUT",
+				expBody:   "a=1&b=x+y",
+			},
+			{
+				label:     "get data",
+				cmd:       "curl -G http://a.b?x=0 -d a=1 -d b=2",
+				expURL:    "http://a.b?x=0&a=1&b=2",
+				expMethod: "GET",
+			},
+			{
+				label:     "basic auth",
+				cmd:       "curl -u alice:pass --url http://a.b",
+				expURL:    "http://a.b",
+				expMethod: "GET",
+				expAuth:   &configfile.AuthRepresentation{Type: "basic", Username: "alice", Password: "env:BENCHTTP_PASSWORD"},
+			},
+			{
+				label:     "bearer auth",
+				cmd:       "curl --oauth2-bearer s3cr3t http://a.b",
+				expURL:    "http://a.b",
+				expMethod: "GET",
+				expAuth:   &configfile.AuthRepresentation{Type: "bearer", Token: "env:BENCHTTP_TOKEN"},
+			},
+		}
+
+		for _, tc := range testcases {
+			t.Run(tc.label, func(t *testing.T) {
+				repr, _, err := importer.CurlCommand(tc.cmd)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := *repr.Request.URL; got != tc.expURL {
+					t.Errorf("url: exp %s, got %s", tc.expURL, got)
+				}
+				if got := *repr.Request.Method; got != tc.expMethod {
+					t.Errorf("method: exp %s, got %s", tc.expMethod, got)
+				}
+				if got := repr.Request.Body; (got == nil) != (tc.expBody == "") || (got != nil && got.Content != tc.expBody) {
+					t.Errorf("body: exp %q, got %#v", tc.expBody, got)
+				}
+				if got := repr.Request.Auth; !reflect.DeepEqual(got, tc.expAuth) {
+					t.Errorf("auth: exp %#v, got %#v", tc.expAuth, got)
+				}
+			})
+		}
+	})
+
+	t.Run("warn about unsupported options", func(t *testing.T) {
+		_, warnings, err := importer.CurlCommand("curl -k -x proxy:8080 -F a=b http://a.b")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []string{"-k", "-x", "-F"}
+		if len(warnings) != len(exp) {
+			t.Fatalf("exp %d warnings, got %v", len(exp), warnings)
+		}
+		for i, opt := range exp {
+			if !strings.Contains(warnings[i], opt) {
+				t.Errorf("warning %d: exp mention of %s, got %q", i, opt, warnings[i])
+			}
+		}
+	})
+
+	t.Run("escape references to environment variables", func(t *testing.T) {
+		repr, _, err := importer.CurlCommand(
+			`curl 'http://a.b/${IMPORT_TEST_PATH}' -H 'x-tpl: ${IMPORT_TEST_USER:-me}' --data-raw '{"a":"${IMPORT_TEST_A}"}'`,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg := parseImported(t, []importer.File{{Name: "curl.yml", Representation: repr}}, "curl.yml")
+		assertLiteralEnvRefs(t, cfg, "/${IMPORT_TEST_PATH}", "x-tpl", "${IMPORT_TEST_USER:-me}", `{"a":"${IMPORT_TEST_A}"}`)
+	})
+
+	t.Run("read credentials from environment variables", func(t *testing.T) {
+		repr, warnings, err := importer.CurlCommand("curl -u '${IMPORT_TEST_USER}:s3cr3t' http://a.b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := &configfile.AuthRepresentation{Type: "basic", Username: "$${IMPORT_TEST_USER}", Password: "env:BENCHTTP_PASSWORD"}
+		if got := repr.Request.Auth; !reflect.DeepEqual(got, exp) {
+			t.Errorf("auth: exp %#v, got %#v", exp, got)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "BENCHTTP_PASSWORD") {
+			t.Errorf("exp warning about BENCHTTP_PASSWORD, got %v", warnings)
+		}
+	})
+
+	t.Run("read credentials headers from environment variables", func(t *testing.T) {
+		repr, warnings, err := importer.CurlCommand(
+			"curl -H 'Cookie: a=1' -b 'b=2' -H 'X-Api-Key: k3y' -H 'X-Note: n' http://a.b",
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := map[string][]string{
+			"Cookie":    {"${BENCHTTP_COOKIE}", "${BENCHTTP_COOKIE_2}"},
+			"X-Api-Key": {"${BENCHTTP_X_API_KEY}"},
+			"X-Note":    {"n"},
+		}
+		if got := repr.Request.Header; !reflect.DeepEqual(got, exp) {
+			t.Errorf("header:\nexp %v\ngot %v", exp, got)
+		}
+		if len(warnings) != 3 {
+			t.Errorf("exp a warning per credential, got %v", warnings)
+		}
+	})
+
+	t.Run("return errors", func(t *testing.T) {
+		for _, cmd := range []string{
+			"curl -H",
+			"curl -X POST",
+			"curl 'http://a.b",
+			"curl -m abc http://a.b",
+		} {
+			if _, _, err := importer.CurlCommand(cmd); err == nil {
+				t.Errorf("exp error for %q, got nil", cmd)
+			}
+		}
+	})
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// parseImported writes the imported files to a temporary directory
+// and returns the config parsed from the one of the given name.
+func parseImported(t *testing.T, files []importer.File, name string) runner.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, f := range files {
+		if err := configfile.Write(filepath.Join(dir, f.Name), f.Representation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := configfile.Parse(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// assertLiteralEnvRefs asserts that the url path, the value of header
+// key and the body of cfg are the given ones, references to environment
+// variables included.
+func assertLiteralEnvRefs(t *testing.T, cfg runner.Config, path, key, value, body string) {
+	t.Helper()
+
+	if got := cfg.Request.URL.Path; got != path {
+		t.Errorf("url path: exp %q, got %q", path, got)
+	}
+	if got := strings.Join(cfg.Request.Header[key], ", "); got != value {
+		t.Errorf("header %s: exp %q, got %q", key, value, got)
+	}
+	if got := string(cfg.Request.Body.Content); got != body {
+		t.Errorf("body: exp %q, got %q", body, got)
+	}
+}
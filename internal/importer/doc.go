@@ -0,0 +1,7 @@
+// Package importer converts requests described in the formats of other
+// tools into representations of benchttp config files, that can be written
+// with configfile.Write and read back with configfile.Parse.
+//
+// Importers never fail on options they cannot represent: they return
+// warnings describing what was left out instead.
+package importer
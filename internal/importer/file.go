@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/redact"
+)
+
+// File is a config file generated by an importer.
+type File struct {
+	// Name is the path of the file, relative to the output directory.
+	Name string
+	// Representation is the content of the file.
+	Representation configfile.Representation
+}
+
+// envRefRgx matches the references to environment variables that are
+// interpolated when a config file is parsed: ${NAME} or ${NAME:-default}.
+var envRefRgx = regexp.MustCompile(`\$\{[A-Za-z_][A-Za-z0-9_]*(?::-[^}]*)?\}`)
+
+// escapeEnv escapes the references to environment variables in the
+// imported value s as "$${NAME}", so that they are read back literally.
+func escapeEnv(s string) string {
+	return envRefRgx.ReplaceAllString(s, "$$$0")
+}
+
+// escapeEnvHeader returns a copy of header with its values escaped,
+// see escapeEnv.
+func escapeEnvHeader(header map[string][]string) map[string][]string {
+	if header == nil {
+		return nil
+	}
+	escaped := make(map[string][]string, len(header))
+	for key, values := range header {
+		for _, v := range values {
+			escaped[key] = append(escaped[key], escapeEnv(v))
+		}
+	}
+	return escaped
+}
+
+// secretHeaderEnv returns a copy of header whose values of the headers
+// holding credentials, as listed by redact.DefaultHeaders, are replaced
+// with references to environment variables, so that they are not written
+// to the config file: the value of Authorization is read from
+// BENCHTTP_AUTHORIZATION, the second one from BENCHTTP_AUTHORIZATION_2.
+// It returns a warning per replaced value.
+func secretHeaderEnv(header map[string][]string) (map[string][]string, []string) {
+	if header == nil {
+		return nil, nil
+	}
+	var warnings []string
+	replaced := make(map[string][]string, len(header))
+	for key, values := range header {
+		if !isSecretHeader(key) {
+			replaced[key] = values
+			continue
+		}
+		for i := range values {
+			name := "BENCHTTP_" + EnvName(key)
+			if i > 0 {
+				name += fmt.Sprintf("_%d", i+1)
+			}
+			replaced[key] = append(replaced[key], "${"+name+"}")
+			warnings = append(warnings, fmt.Sprintf(
+				"the value of header %s is read from environment variable %s: set it before running", key, name,
+			))
+		}
+	}
+	sort.Strings(warnings)
+	return replaced, warnings
+}
+
+// isSecretHeader returns true if the header key holds credentials.
+func isSecretHeader(key string) bool {
+	for _, name := range redact.DefaultHeaders {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonSlugRgx matches the sequences of characters that are replaced
+// in file names.
+var nonSlugRgx = regexp.MustCompile(`[^a-z0-9]+`)
+
+// maxSlugLen is the maximum length of a slug, to keep file names short.
+const maxSlugLen = 60
+
+// slugify returns a lowercase version of s suitable for a file name.
+func slugify(s string) string {
+	slug := strings.Trim(nonSlugRgx.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > maxSlugLen {
+		slug = strings.TrimRight(slug[:maxSlugLen], "-")
+	}
+	return slug
+}
+
+// uniqueNames returns unique file names from a base name and an extension,
+// suffixing duplicates with a counter.
+type uniqueNames map[string]int
+
+// next returns a file name for base that was not returned yet.
+func (names uniqueNames) next(base, ext string) string {
+	names[base]++
+	if n := names[base]; n > 1 {
+		return fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+	return base + ext
+}
+
+// globRgx returns a regexp matching the strings matched by the glob
+// pattern, where "*" matches any sequence of characters and "?" any
+// single character.
+func globRgx(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// matchGlob returns true if s matches the glob pattern. An empty pattern
+// matches everything.
+func matchGlob(pattern, s string) bool {
+	return pattern == "" || globRgx(pattern).MatchString(s)
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// ErrHAR signals an invalid HAR file.
+var ErrHAR = errors.New("invalid HAR file")
+
+// VolatileHeaders lists the headers that are specific to a browsing
+// session or to the connection, and are stripped from imported requests.
+// A trailing "*" matches any suffix.
+var VolatileHeaders = []string{
+	":*", // HTTP/2 pseudo-headers
+	"Connection",
+	"Content-Length",
+	"Host",
+	"If-Modified-Since",
+	"If-None-Match",
+	"Keep-Alive",
+	"Priority",
+	"Sec-Ch-Ua*",
+	"Sec-Fetch-*",
+	"Upgrade-Insecure-Requests",
+}
+
+// HAROptions configures the import of a HAR file.
+type HAROptions struct {
+	// Host is a glob pattern matching the host of the entries to import.
+	Host string
+	// Path is a glob pattern matching the path of the entries to import.
+	Path string
+	// StripHeaders is a list of header name patterns stripped in addition
+	// to VolatileHeaders.
+	StripHeaders []string
+	// Extends moves the headers shared by all imported requests
+	// to a base file, that the other files extend.
+	Extends bool
+	// Ext is the extension of the generated files, defaults to ".yml".
+	Ext string
+}
+
+// BaseFileName is the name of the base file generated with
+// HAROptions.Extends, without extension.
+const BaseFileName = "base"
+
+// harLog is the subset of the HAR 1.2 format read by the importer.
+type harLog struct {
+	Log struct {
+		Entries []struct {
+			Request harRequest `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harRequest struct {
+	Method  string `json:"method"`
+	URL     string `json:"url"`
+	Headers []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"headers"`
+	PostData *struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Params   []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"params"`
+	} `json:"postData"`
+}
+
+// HAR converts the entries of a HAR file matching opts into config files,
+// one per request. The values of the headers holding credentials, such
+// as Cookie or Authorization, are read from environment variables, see
+// secretHeaderEnv. It returns warnings about the entries that were
+// skipped and the credentials to set.
+func HAR(r io.Reader, opts HAROptions) ([]File, []string, error) {
+	var har harLog
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, nil, errorutil.WithDetails(ErrHAR, err)
+	}
+
+	if opts.Ext == "" {
+		opts.Ext = ".yml"
+	}
+
+	var (
+		files    []File
+		warnings []string
+		names    = uniqueNames{BaseFileName: 1} // reserve base name
+		strip    = append(append([]string{}, VolatileHeaders...), opts.StripHeaders...)
+	)
+
+	for i, entry := range har.Log.Entries {
+		req := entry.Request
+
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			warnings = append(warnings, fmt.Sprintf("entry %d: unsupported url %q: skipped", i, req.URL))
+			continue
+		}
+
+		if !matchGlob(opts.Host, u.Hostname()) || !matchGlob(opts.Path, u.Path) {
+			continue
+		}
+
+		name := names.next(slugify(req.Method+"-"+u.Hostname()+"-"+u.Path), opts.Ext)
+		repr := harRepresentation(req, strip)
+
+		// the credentials of the session are the same for all requests:
+		// warn once about each of them
+		var headerWarnings []string
+		repr.Request.Header, headerWarnings = secretHeaderEnv(repr.Request.Header)
+		for _, w := range headerWarnings {
+			if !containsString(warnings, w) {
+				warnings = append(warnings, w)
+			}
+		}
+
+		files = append(files, File{Name: name, Representation: repr})
+	}
+
+	if opts.Extends && len(files) > 1 {
+		files = extractBase(files, opts.Ext)
+	}
+
+	return files, warnings, nil
+}
+
+// harRepresentation returns the representation of the config file
+// equivalent to req, without the headers matching strip.
+func harRepresentation(req harRequest, strip []string) configfile.Representation {
+	var repr configfile.Representation
+
+	method, rawURL := req.Method, escapeEnv(req.URL)
+	repr.Request.Method = &method
+	repr.Request.URL = &rawURL
+
+	header := map[string][]string{}
+	for _, h := range req.Headers {
+		if matchAnyHeader(strip, h.Name) {
+			continue
+		}
+		header[h.Name] = append(header[h.Name], escapeEnv(h.Value))
+	}
+	if len(header) != 0 {
+		repr.Request.Header = header
+	}
+
+	if data := req.PostData; data != nil {
+		content := data.Text
+		if content == "" && len(data.Params) != 0 {
+			form := url.Values{}
+			for _, p := range data.Params {
+				form.Add(p.Name, p.Value)
+			}
+			content = form.Encode()
+		}
+		if content != "" {
+			repr.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: escapeEnv(content)}
+		}
+	}
+
+	return repr
+}
+
+// matchAnyHeader returns true if name matches any of the case-insensitive
+// glob patterns.
+func matchAnyHeader(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if matchGlob(strings.ToLower(p), strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractBase moves the headers shared by all files, with the same
+// values, to a base file that the other files extend.
+func extractBase(files []File, ext string) []File {
+	shared := map[string][]string{}
+	for key, values := range files[0].Representation.Request.Header {
+		shared[key] = values
+	}
+	for _, f := range files[1:] {
+		header := f.Representation.Request.Header
+		for key, values := range shared {
+			if !equalStrings(header[key], values) {
+				delete(shared, key)
+			}
+		}
+	}
+
+	if len(shared) == 0 {
+		return files
+	}
+
+	baseName := BaseFileName + ext
+	for i := range files {
+		for key := range shared {
+			delete(files[i].Representation.Request.Header, key)
+		}
+		if len(files[i].Representation.Request.Header) == 0 {
+			files[i].Representation.Request.Header = nil
+		}
+		files[i].Representation.Extends = &baseName
+	}
+
+	base := File{Name: baseName}
+	base.Representation.Request.Header = shared
+
+	return append([]File{base}, files...)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
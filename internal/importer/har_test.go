@@ -0,0 +1,233 @@
+package importer_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/importer"
+)
+
+const harInput = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {
+        "request": {
+          "method": "GET",
+          "url": "https://api.example.com/users?page=2",
+          "headers": [
+            {"name": ":authority", "value": "api.example.com"},
+            {"name": "accept", "value": "application/json"},
+            {"name": "x-client", "value": "web"},
+            {"name": "if-none-match", "value": "W/\"abc\""},
+            {"name": "sec-fetch-mode", "value": "cors"}
+          ]
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/users",
+          "headers": [
+            {"name": "accept", "value": "application/json"},
+            {"name": "x-client", "value": "web"},
+            {"name": "content-type", "value": "application/json"},
+            {"name": "content-length", "value": "12"}
+          ],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"a\"}"}
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/login",
+          "headers": [{"name": "accept", "value": "text/html"}],
+          "postData": {
+            "mimeType": "application/x-www-form-urlencoded",
+            "params": [{"name": "user", "value": "a b"}]
+          }
+        }
+      },
+      {
+        "request": {
+          "method": "GET",
+          "url": "https://cdn.example.com/app.js",
+          "headers": []
+        }
+      },
+      {
+        "request": {
+          "method": "GET",
+          "url": "data:image/png;base64,AAAA",
+          "headers": []
+        }
+      }
+    ]
+  }
+}`
+
+func TestHAR(t *testing.T) {
+	t.Run("convert entries to config files", func(t *testing.T) {
+		files, warnings, err := importer.HAR(strings.NewReader(harInput), importer.HAROptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("exp 1 warning for the data url, got %v", warnings)
+		}
+
+		expNames := []string{
+			"get-api-example-com-users.yml",
+			"post-api-example-com-users.yml",
+			"post-api-example-com-login.yml",
+			"get-cdn-example-com-app-js.yml",
+		}
+		if gotNames := fileNames(files); !reflect.DeepEqual(gotNames, expNames) {
+			t.Fatalf("bad file names:\nexp %v\ngot %v", expNames, gotNames)
+		}
+
+		exp := configfile.Representation{}
+		exp.Request.Method = ptr("GET")
+		exp.Request.URL = ptr("https://api.example.com/users?page=2")
+		exp.Request.Header = map[string][]string{
+			"accept":   {"application/json"},
+			"x-client": {"web"},
+		}
+		if got := files[0].Representation; !reflect.DeepEqual(got, exp) {
+			t.Errorf("bad representation:\nexp %#v\ngot %#v", exp, got)
+		}
+
+		body := files[1].Representation.Request.Body
+		if body == nil || body.Content != `{"name":"a"}` {
+			t.Errorf("bad body: %#v", body)
+		}
+		if _, ok := files[1].Representation.Request.Header["content-length"]; ok {
+			t.Error("volatile header content-length not stripped")
+		}
+
+		form := files[2].Representation.Request.Body
+		if form == nil || form.Content != "user=a+b" {
+			t.Errorf("bad form body: %#v", form)
+		}
+	})
+
+	t.Run("filter by host and path", func(t *testing.T) {
+		files, _, err := importer.HAR(strings.NewReader(harInput), importer.HAROptions{
+			Host: "api.*",
+			Path: "/users*",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 2 {
+			t.Errorf("exp 2 files, got %v", fileNames(files))
+		}
+	})
+
+	t.Run("strip extra headers", func(t *testing.T) {
+		files, _, err := importer.HAR(strings.NewReader(harInput), importer.HAROptions{
+			StripHeaders: []string{"X-*"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := files[0].Representation.Request.Header["x-client"]; ok {
+			t.Error("header x-client not stripped")
+		}
+	})
+
+	t.Run("extract shared headers to base file", func(t *testing.T) {
+		files, _, err := importer.HAR(strings.NewReader(harInput), importer.HAROptions{
+			Host:    "api.example.com",
+			Path:    "/users",
+			Extends: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 3 || files[0].Name != "base.yml" {
+			t.Fatalf("exp base file first, got %v", fileNames(files))
+		}
+
+		expShared := map[string][]string{
+			"accept":   {"application/json"},
+			"x-client": {"web"},
+		}
+		if got := files[0].Representation.Request.Header; !reflect.DeepEqual(got, expShared) {
+			t.Errorf("bad shared headers:\nexp %v\ngot %v", expShared, got)
+		}
+
+		for _, f := range files[1:] {
+			if f.Representation.Extends == nil || *f.Representation.Extends != "base.yml" {
+				t.Errorf("%s: exp extends base.yml", f.Name)
+			}
+		}
+		if files[1].Representation.Request.Header != nil {
+			t.Errorf("exp no remaining header, got %v", files[1].Representation.Request.Header)
+		}
+		if got := files[2].Representation.Request.Header; len(got) != 1 {
+			t.Errorf("exp only content-type header, got %v", got)
+		}
+	})
+
+	t.Run("escape references to environment variables", func(t *testing.T) {
+		in := `{"log": {"entries": [{"request": {
+			"method": "POST",
+			"url": "https://api.example.com/${IMPORT_TEST_PATH}",
+			"headers": [{"name": "x-tpl", "value": "${IMPORT_TEST_USER:-me}"}],
+			"postData": {"mimeType": "application/json", "text": "{\"a\":\"${IMPORT_TEST_A}\"}"}
+		}}]}}`
+		files, _, err := importer.HAR(strings.NewReader(in), importer.HAROptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg := parseImported(t, files, files[0].Name)
+		assertLiteralEnvRefs(t, cfg, "/${IMPORT_TEST_PATH}", "x-tpl", "${IMPORT_TEST_USER:-me}", `{"a":"${IMPORT_TEST_A}"}`)
+	})
+
+	t.Run("read credentials from environment variables", func(t *testing.T) {
+		input := `{"log": {"entries": [
+			{"request": {"method": "GET", "url": "https://a.b/x", "headers": [
+				{"name": "cookie", "value": "session=s3cr3t"},
+				{"name": "authorization", "value": "Bearer t0k3n"}
+			]}},
+			{"request": {"method": "GET", "url": "https://a.b/y", "headers": [
+				{"name": "cookie", "value": "session=s3cr3t"}
+			]}}
+		]}}`
+		files, warnings, err := importer.HAR(strings.NewReader(input), importer.HAROptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := map[string][]string{
+			"cookie":        {"${BENCHTTP_COOKIE}"},
+			"authorization": {"${BENCHTTP_AUTHORIZATION}"},
+		}
+		if got := files[0].Representation.Request.Header; !reflect.DeepEqual(got, exp) {
+			t.Errorf("header:\nexp %v\ngot %v", exp, got)
+		}
+		if len(warnings) != 2 {
+			t.Errorf("exp a warning per credential, got %v", warnings)
+		}
+	})
+
+	t.Run("return ErrHAR for invalid input", func(t *testing.T) {
+		_, _, err := importer.HAR(strings.NewReader("{"), importer.HAROptions{})
+		if !errors.Is(err, importer.ErrHAR) {
+			t.Errorf("exp ErrHAR, got %v", err)
+		}
+	})
+}
+
+func fileNames(files []importer.File) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+	return names
+}
@@ -0,0 +1,443 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// ErrOpenAPI signals an OpenAPI specification that cannot be converted.
+var ErrOpenAPI = errors.New("invalid OpenAPI specification")
+
+// OpenAPIOptions configures the import of an OpenAPI specification.
+type OpenAPIOptions struct {
+	// Server is the base URL of the requests. It defaults to the first
+	// server of the specification.
+	Server string
+	// Operations restricts the import to the operations with the given ids.
+	Operations []string
+	// Tags restricts the import to the operations with any of the given tags.
+	Tags []string
+	// Ext is the extension of the generated files, defaults to ".yml".
+	Ext string
+}
+
+// openAPIMethods lists the operation keys of a path item, in the order
+// of the specification.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// maxRefDepth is the maximum depth of nested references followed
+// when resolving a value, to prevent infinite recursion.
+const maxRefDepth = 16
+
+// object is a generic OpenAPI object.
+type object = map[string]interface{}
+
+// openAPISpec is a decoded OpenAPI specification.
+type openAPISpec struct {
+	root     object
+	warnings []string
+}
+
+// OpenAPI converts the operations of an OpenAPI 3 specification, in YAML
+// or JSON, into config files, one per operation. Path parameters and
+// request bodies are filled from the examples of the specification,
+// or generated from their schemas. It returns warnings about the values
+// that had to be generated.
+func OpenAPI(r io.Reader, opts OpenAPIOptions) ([]File, []string, error) {
+	var root interface{}
+	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, nil, errorutil.WithDetails(ErrOpenAPI, err)
+	}
+
+	spec := &openAPISpec{root: asObject(normalize(root))}
+	if version, _ := spec.root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, nil, errorutil.WithDetails(ErrOpenAPI, "unsupported version: want openapi 3.x")
+	}
+
+	server, err := spec.serverURL(opts.Server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Ext == "" {
+		opts.Ext = ".yml"
+	}
+
+	paths := asObject(spec.root["paths"])
+	files := []File{}
+	names := uniqueNames{}
+
+	for _, path := range sortedKeys(paths) {
+		item := spec.resolve(paths[path])
+		for _, method := range openAPIMethods {
+			op := asObject(item[method])
+			if op == nil || !opts.selects(op) {
+				continue
+			}
+
+			repr := spec.representation(server, path, method, item, op)
+
+			name, _ := op["operationId"].(string)
+			if name == "" {
+				name = method + "-" + path
+			}
+			files = append(files, File{
+				Name:           names.next(slugify(name), opts.Ext),
+				Representation: repr,
+			})
+		}
+	}
+
+	return files, spec.warnings, nil
+}
+
+// selects returns true if op is selected by the options.
+func (opts OpenAPIOptions) selects(op object) bool {
+	if len(opts.Operations) != 0 {
+		id, _ := op["operationId"].(string)
+		if !containsString(opts.Operations, id) {
+			return false
+		}
+	}
+	if len(opts.Tags) != 0 {
+		tags, _ := op["tags"].([]interface{})
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok && containsString(opts.Tags, s) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (spec *openAPISpec) warnf(format string, args ...interface{}) {
+	spec.warnings = append(spec.warnings, fmt.Sprintf(format, args...))
+}
+
+// serverURL returns the base URL of the requests: override if set, else
+// the first server of the spec with its variables set to their defaults.
+func (spec *openAPISpec) serverURL(override string) (string, error) {
+	server := override
+	if server == "" {
+		servers, _ := spec.root["servers"].([]interface{})
+		if len(servers) != 0 {
+			first := asObject(servers[0])
+			server, _ = first["url"].(string)
+			for name, v := range asObject(first["variables"]) {
+				def := fmt.Sprint(asObject(v)["default"])
+				server = strings.ReplaceAll(server, "{"+name+"}", def)
+			}
+		}
+	}
+
+	if u, err := url.Parse(server); err != nil || !u.IsAbs() {
+		return "", errorutil.WithDetails(ErrOpenAPI,
+			fmt.Sprintf("no absolute server url (got %q): set one explicitly", server))
+	}
+	return strings.TrimSuffix(server, "/"), nil
+}
+
+// representation returns the config file representation
+// of the operation op.
+func (spec *openAPISpec) representation(server, path, method string, item, op object) configfile.Representation {
+	var repr configfile.Representation
+	opName := strings.ToUpper(method) + " " + path
+
+	query := url.Values{}
+	header := map[string][]string{}
+
+	for _, param := range spec.parameters(item, op) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+
+		value, ok := spec.example(param)
+		if !ok && !required {
+			continue // optional parameters are only set when documented
+		}
+		if !ok {
+			value = spec.generate(asObject(param["schema"]), 0)
+			if in == "path" {
+				spec.warnf("%s: no example for path parameter %s: generated %v", opName, name, value)
+			}
+		}
+		str := fmt.Sprint(value)
+
+		switch in {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(str))
+		case "query":
+			query.Add(name, str)
+		case "header":
+			header[name] = append(header[name], str)
+		}
+	}
+
+	rawURL := server + path
+	if len(query) != 0 {
+		rawURL += "?" + query.Encode()
+	}
+	rawURL = escapeEnv(rawURL)
+	upperMethod := strings.ToUpper(method)
+	repr.Request.Method = &upperMethod
+	repr.Request.URL = &rawURL
+
+	if mimeType, content, ok := spec.body(opName, op); ok {
+		header["Content-Type"] = []string{mimeType}
+		repr.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: escapeEnv(content)}
+	}
+
+	if len(header) != 0 {
+		repr.Request.Header = escapeEnvHeader(header)
+	}
+
+	return repr
+}
+
+// parameters returns the resolved parameters of op, including those
+// defined at the path level that op does not override.
+func (spec *openAPISpec) parameters(item, op object) []object {
+	var params []object
+	seen := map[string]bool{}
+	add := func(list interface{}) {
+		raw, _ := list.([]interface{})
+		for _, p := range raw {
+			param := spec.resolve(p)
+			key := fmt.Sprint(param["in"], ":", param["name"])
+			if param == nil || seen[key] {
+				continue
+			}
+			seen[key] = true
+			params = append(params, param)
+		}
+	}
+	add(op["parameters"]) // operation parameters take precedence
+	add(item["parameters"])
+	return params
+}
+
+// body returns the content type and the content of the request body
+// of op, preferring JSON content.
+func (spec *openAPISpec) body(opName string, op object) (mimeType, content string, ok bool) {
+	reqBody := spec.resolve(op["requestBody"])
+	contents := asObject(reqBody["content"])
+	if len(contents) == 0 {
+		return "", "", false
+	}
+
+	mimeTypes := sortedKeys(contents)
+	mimeType = mimeTypes[0]
+	for _, m := range mimeTypes {
+		if strings.Contains(m, "json") {
+			mimeType = m
+			break
+		}
+	}
+
+	media := asObject(contents[mimeType])
+	value, found := spec.example(media)
+	if !found {
+		value = spec.generate(asObject(media["schema"]), 0)
+		spec.warnf("%s: no example for request body: generated from schema", opName)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return mimeType, v, true
+	case object:
+		if strings.Contains(mimeType, "x-www-form-urlencoded") {
+			form := url.Values{}
+			for _, k := range sortedKeys(v) {
+				form.Set(k, fmt.Sprint(v[k]))
+			}
+			return mimeType, form.Encode(), true
+		}
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		spec.warnf("%s: cannot encode request body: %s", opName, err)
+		return "", "", false
+	}
+	return mimeType, string(b), true
+}
+
+// example returns the example value of a parameter or a media type
+// object, looking in this order at its fields example, examples
+// and the example or default value of its schema.
+func (spec *openAPISpec) example(obj object) (interface{}, bool) {
+	if v, ok := obj["example"]; ok {
+		return v, true
+	}
+	examples := asObject(obj["examples"])
+	if keys := sortedKeys(examples); len(keys) != 0 {
+		if v, ok := spec.resolve(examples[keys[0]])["value"]; ok {
+			return v, true
+		}
+	}
+	schema := spec.resolve(obj["schema"])
+	if v, ok := schema["example"]; ok {
+		return v, true
+	}
+	if v, ok := schema["default"]; ok {
+		return v, true
+	}
+	return nil, false
+}
+
+// generate returns a value conforming to schema, built from the examples
+// and defaults of its properties, or from placeholder values.
+func (spec *openAPISpec) generate(schema object, depth int) interface{} {
+	schema = spec.resolve(schema)
+	if depth > maxRefDepth {
+		return nil
+	}
+	if v, ok := schema["example"]; ok {
+		return v
+	}
+	if v, ok := schema["default"]; ok {
+		return v
+	}
+	if enum, _ := schema["enum"].([]interface{}); len(enum) != 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if list, _ := schema[key].([]interface{}); len(list) != 0 {
+			if key != "allOf" {
+				return spec.generate(asObject(list[0]), depth+1)
+			}
+			merged := object{}
+			for _, sub := range list {
+				if m, ok := spec.generate(asObject(sub), depth+1).(object); ok {
+					for k, v := range m {
+						merged[k] = v
+					}
+				}
+			}
+			return merged
+		}
+	}
+
+	switch schema["type"] {
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{spec.generate(asObject(schema["items"]), depth+1)}
+	case "string":
+		return placeholderString(schema)
+	}
+
+	props := asObject(schema["properties"])
+	if props == nil {
+		if schema["type"] == "object" {
+			return object{}
+		}
+		return "string"
+	}
+	obj := object{}
+	for _, name := range sortedKeys(props) {
+		obj[name] = spec.generate(asObject(props[name]), depth+1)
+	}
+	return obj
+}
+
+// placeholderString returns a placeholder value for a string schema,
+// matching its format when possible.
+func placeholderString(schema object) string {
+	switch schema["format"] {
+	case "date":
+		return "2006-01-02"
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	}
+	return "string"
+}
+
+// resolve returns v as an object, following local references
+// ("#/components/...").
+func (spec *openAPISpec) resolve(v interface{}) object {
+	obj := asObject(v)
+	for i := 0; i < maxRefDepth; i++ {
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return obj
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			spec.warnf("unsupported external reference %s: ignored", ref)
+			return nil
+		}
+		var target interface{} = spec.root
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			key = strings.NewReplacer("~1", "/", "~0", "~").Replace(key)
+			target = asObject(target)[key]
+		}
+		obj = asObject(target)
+	}
+	return obj
+}
+
+// normalize converts recursively the maps decoded with non-string keys
+// to objects, so that they can be encoded to JSON.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(object, len(v))
+		for k, val := range v {
+			obj[fmt.Sprint(k)] = normalize(val)
+		}
+		return obj
+	case object:
+		for k, val := range v {
+			v[k] = normalize(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	}
+	return v
+}
+
+func asObject(v interface{}) object {
+	obj, _ := v.(object)
+	return obj
+}
+
+func sortedKeys(obj object) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package importer_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/importer"
+)
+
+const openAPIInput = `
+openapi: 3.0.3
+servers:
+  - url: "{scheme}://api.example.com/v1"
+    variables:
+      scheme:
+        default: https
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      tags: [users]
+      parameters:
+        - name: page
+          in: query
+          example: 2
+        - name: filter
+          in: query
+        - $ref: "#/components/parameters/Client"
+    post:
+      operationId: createUser
+      tags: [users]
+      requestBody:
+        $ref: "#/components/requestBodies/User"
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      operationId: getUser
+      tags: [users]
+  /health:
+    get:
+      tags: [ops]
+components:
+  parameters:
+    Client:
+      name: X-Client
+      in: header
+      required: true
+      schema:
+        type: string
+        default: bench
+  requestBodies:
+    User:
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/User"
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+          example: gopher
+        age:
+          type: integer
+`
+
+func TestOpenAPI(t *testing.T) {
+	t.Run("convert operations to config files", func(t *testing.T) {
+		files, warnings, err := importer.OpenAPI(strings.NewReader(openAPIInput), importer.OpenAPIOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expNames := []string{"get-health.yml", "listusers.yml", "createuser.yml", "getuser.yml"}
+		if got := fileNames(files); !reflect.DeepEqual(got, expNames) {
+			t.Fatalf("bad file names:\nexp %v\ngot %v", expNames, got)
+		}
+
+		exp := configfile.Representation{}
+		exp.Request.Method = ptr("GET")
+		exp.Request.URL = ptr("https://api.example.com/v1/users?page=2")
+		exp.Request.Header = map[string][]string{"X-Client": {"bench"}}
+		if got := files[1].Representation; !reflect.DeepEqual(got, exp) {
+			t.Errorf("bad representation:\nexp %#v\ngot %#v", exp, got)
+		}
+
+		create := files[2].Representation.Request
+		if create.Body == nil || create.Body.Content != `{"age":1,"name":"gopher"}` {
+			t.Errorf("bad generated body: %#v", create.Body)
+		}
+		if got := create.Header["Content-Type"]; !reflect.DeepEqual(got, []string{"application/json"}) {
+			t.Errorf("bad content type: %v", got)
+		}
+
+		if got := *files[3].Representation.Request.URL; got != "https://api.example.com/v1/users/1" {
+			t.Errorf("bad path parameter: %s", got)
+		}
+
+		if len(warnings) != 2 {
+			t.Errorf("exp warnings for path parameter and body, got %v", warnings)
+		}
+	})
+
+	t.Run("generated files are valid config files", func(t *testing.T) {
+		files, _, err := importer.OpenAPI(strings.NewReader(openAPIInput), importer.OpenAPIOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		for _, f := range files {
+			filename := filepath.Join(dir, f.Name)
+			if err := configfile.Write(filename, f.Representation); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := configfile.Parse(filename); err != nil {
+				t.Errorf("%s: %v", f.Name, err)
+			}
+		}
+	})
+
+	t.Run("select operations by id and tag", func(t *testing.T) {
+		files, _, err := importer.OpenAPI(strings.NewReader(openAPIInput), importer.OpenAPIOptions{
+			Operations: []string{"getUser", "createUser"},
+			Tags:       []string{"users"},
+			Server:     "http://localhost:8080/",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fileNames(files); !reflect.DeepEqual(got, []string{"createuser.yml", "getuser.yml"}) {
+			t.Errorf("bad selection: %v", got)
+		}
+		if got := *files[1].Representation.Request.URL; got != "http://localhost:8080/users/1" {
+			t.Errorf("server not overridden: %s", got)
+		}
+	})
+
+	t.Run("escape references to environment variables", func(t *testing.T) {
+		in := `
+openapi: 3.0.3
+servers:
+  - url: "https://api.example.com"
+paths:
+  /${IMPORT_TEST_PATH}:
+    post:
+      parameters:
+        - {name: x-tpl, in: header, required: true, example: "${IMPORT_TEST_USER:-me}"}
+      requestBody:
+        content:
+          application/json:
+            example: {a: "${IMPORT_TEST_A}"}
+`
+		files, _, err := importer.OpenAPI(strings.NewReader(in), importer.OpenAPIOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg := parseImported(t, files, files[0].Name)
+		assertLiteralEnvRefs(t, cfg, "/${IMPORT_TEST_PATH}", "x-tpl", "${IMPORT_TEST_USER:-me}", `{"a":"${IMPORT_TEST_A}"}`)
+	})
+
+	t.Run("return ErrOpenAPI for unsupported input", func(t *testing.T) {
+		for _, in := range []string{
+			"swagger: '2.0'",
+			"openapi: 3.0.0\nservers: [{url: /v1}]",
+			"{",
+		} {
+			_, _, err := importer.OpenAPI(strings.NewReader(in), importer.OpenAPIOptions{})
+			if !errors.Is(err, importer.ErrOpenAPI) {
+				t.Errorf("%q: exp ErrOpenAPI, got %v", in, err)
+			}
+		}
+	})
+}
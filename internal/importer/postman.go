@@ -0,0 +1,513 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// ErrPostman signals an invalid Postman collection.
+var ErrPostman = errors.New("invalid Postman collection")
+
+// PostmanOptions configures the import of a Postman collection.
+type PostmanOptions struct {
+	// Ext is the extension of the generated files, defaults to ".yml".
+	Ext string
+}
+
+// postmanCollection is the subset of the Postman collection v2.1 format
+// read by the importer.
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem `json:"item"`
+	Auth     *postmanAuth  `json:"auth"`
+	Variable []postmanKV   `json:"variable"`
+}
+
+// postmanItem is either a folder, with items, or a request.
+type postmanItem struct {
+	Name    string            `json:"name"`
+	Item    []postmanItem     `json:"item"`
+	Request *postmanRequest   `json:"request"`
+	Auth    *postmanAuth      `json:"auth"`
+	Event   []json.RawMessage `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string       `json:"method"`
+	URL    postmanURL   `json:"url"`
+	Header []postmanKV  `json:"header"`
+	Body   *postmanBody `json:"body"`
+	Auth   *postmanAuth `json:"auth"`
+}
+
+// postmanURL is a request URL, described either as a string
+// or as an object with a raw field.
+type postmanURL string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *postmanURL) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err == nil {
+		*u = postmanURL(raw)
+		return nil
+	}
+	var obj struct {
+		Raw string `json:"raw"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	*u = postmanURL(obj.Raw)
+	return nil
+}
+
+type postmanKV struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+}
+
+// value returns the value of kv as a string.
+func (kv postmanKV) value() string {
+	if kv.Value == nil {
+		return ""
+	}
+	if s, ok := kv.Value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(kv.Value)
+}
+
+type postmanBody struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw"`
+	URLEncoded []postmanKV `json:"urlencoded"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type postmanAuth struct {
+	Type   string      `json:"type"`
+	Bearer []postmanKV `json:"bearer"`
+	Basic  []postmanKV `json:"basic"`
+	APIKey []postmanKV `json:"apikey"`
+}
+
+// param returns the value of the auth parameter key of the given list.
+func (postmanAuth) param(params []postmanKV, key string) string {
+	for _, p := range params {
+		if p.Key == key {
+			return p.value()
+		}
+	}
+	return ""
+}
+
+// postmanNode is a folder of the collection, written as a directory
+// with an optional base file extended by its requests and subfolders.
+type postmanNode struct {
+	dir      string
+	base     configfile.Representation
+	requests []File
+	children []*postmanNode
+
+	// noAuthRequests are the requests without auth in a folder with
+	// an inherited auth. As an auth cannot be removed with extends,
+	// they do not extend the base file.
+	noAuthRequests []File
+
+	// noAuth is true for a folder without auth in a folder with an auth:
+	// it does not extend the base file of its parent.
+	noAuth bool
+}
+
+// postmanImporter holds the state of a Postman collection import.
+type postmanImporter struct {
+	ext       string
+	variables map[string]string
+	warnings  []string
+
+	// defaulted are the collection variables written as the default
+	// values of environment variables
+	defaulted map[string]bool
+}
+
+// Postman converts the requests of a Postman collection (format v2.1)
+// into config files. Folders are written as directories with a base file
+// holding their auth and the headers shared by all their requests, that
+// the files of the folder extend. Postman variables ({{name}}) are mapped
+// to environment variables (${NAME}), defaulting to the values of the
+// collection variables.
+func Postman(r io.Reader, opts PostmanOptions) ([]File, []string, error) {
+	var collection postmanCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, nil, errorutil.WithDetails(ErrPostman, err)
+	}
+	if !strings.Contains(collection.Info.Schema, "collection/v2") {
+		return nil, nil, errorutil.WithDetails(ErrPostman, "unsupported schema: want collection v2.x")
+	}
+
+	if opts.Ext == "" {
+		opts.Ext = ".yml"
+	}
+
+	imp := &postmanImporter{ext: opts.Ext, variables: map[string]string{}, defaulted: map[string]bool{}}
+	for _, v := range collection.Variable {
+		if !v.Disabled {
+			imp.variables[v.Key] = v.value()
+		}
+	}
+
+	root := imp.node("", collection.Info.Name, collection.Item, collection.Auth, false)
+	root.hoistHeaders()
+
+	return root.files(imp.ext, ""), imp.warnings, nil
+}
+
+func (imp *postmanImporter) warnf(format string, args ...interface{}) {
+	imp.warnings = append(imp.warnings, fmt.Sprintf(format, args...))
+}
+
+// node returns the node of a folder and its items, written in dir.
+// hasAuth is true if the folder inherits an auth from its parents.
+func (imp *postmanImporter) node(dir, name string, items []postmanItem, auth *postmanAuth, hasAuth bool) *postmanNode {
+	n := &postmanNode{dir: dir, noAuth: hasAuth && auth.isNoAuth()}
+	imp.setAuth(&n.base, name, auth)
+	hasAuth = (hasAuth || auth != nil) && !auth.isNoAuth()
+
+	names := uniqueNames{BaseFileName: 1} // reserve base name
+	for _, item := range items {
+		if len(item.Event) != 0 {
+			imp.warnf("%s: scripts are not supported: ignored", item.Name)
+		}
+
+		if item.Request == nil {
+			childDir := filepath.Join(dir, names.next(slugify(item.Name), ""))
+			n.children = append(n.children, imp.node(childDir, item.Name, item.Item, item.Auth, hasAuth))
+			continue
+		}
+
+		f := File{
+			Name:           filepath.Join(dir, names.next(slugify(item.Name), imp.ext)),
+			Representation: imp.representation(item.Name, *item.Request),
+		}
+		if hasAuth && item.Request.Auth.isNoAuth() {
+			n.noAuthRequests = append(n.noAuthRequests, f)
+			continue
+		}
+		n.requests = append(n.requests, f)
+	}
+
+	return n
+}
+
+// representation returns the config file representation of req.
+func (imp *postmanImporter) representation(name string, req postmanRequest) configfile.Representation {
+	var repr configfile.Representation
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+	rawURL := imp.replaceVars(string(req.URL))
+	repr.Request.Method = &method
+	repr.Request.URL = &rawURL
+
+	header := map[string][]string{}
+	for _, h := range req.Header {
+		if !h.Disabled {
+			key := imp.replaceVars(h.Key)
+			header[key] = append(header[key], imp.replaceVars(h.value()))
+		}
+	}
+
+	if body := req.Body; body != nil {
+		contentType, content := imp.body(name, *body)
+		if content != "" {
+			repr.Request.Body = &configfile.BodyRepresentation{Type: "raw", Content: content}
+		}
+		if contentType != "" && !hasHeader(header, "Content-Type") {
+			header["Content-Type"] = []string{contentType}
+		}
+	}
+
+	if len(header) != 0 {
+		repr.Request.Header = header
+	}
+
+	imp.setAuth(&repr, name, req.Auth)
+
+	return repr
+}
+
+// body returns the default content type and the content of body.
+func (imp *postmanImporter) body(name string, body postmanBody) (contentType, content string) {
+	switch body.Mode {
+	case "raw":
+		if body.Options.Raw.Language == "json" {
+			contentType = "application/json"
+		}
+		return contentType, imp.replaceVars(body.Raw)
+	case "urlencoded":
+		var pairs []string
+		for _, p := range body.URLEncoded {
+			if !p.Disabled {
+				pairs = append(pairs, queryEscapeVars(p.Key)+"="+queryEscapeVars(p.value()))
+			}
+		}
+		return "application/x-www-form-urlencoded", imp.replaceVars(strings.Join(pairs, "&"))
+	case "graphql":
+		if body.GraphQL == nil {
+			return "", ""
+		}
+		payload := map[string]interface{}{"query": body.GraphQL.Query}
+		if vars := strings.TrimSpace(body.GraphQL.Variables); vars != "" {
+			payload["variables"] = json.RawMessage(vars)
+		}
+		b, err := json.Marshal(payload)
+		if err != nil {
+			imp.warnf("%s: invalid graphql variables: ignored", name)
+			b, _ = json.Marshal(map[string]string{"query": body.GraphQL.Query})
+		}
+		return "application/json", imp.replaceVars(string(b))
+	case "":
+		return "", ""
+	default:
+		imp.warnf("%s: unsupported body mode %s: ignored", name, body.Mode)
+		return "", ""
+	}
+}
+
+// setAuth sets the auth of repr from the Postman auth of item name.
+func (imp *postmanImporter) setAuth(repr *configfile.Representation, name string, auth *postmanAuth) {
+	if auth == nil {
+		return
+	}
+
+	switch auth.Type {
+	case "bearer":
+		repr.Request.Auth = &configfile.AuthRepresentation{
+			Type:  "bearer",
+			Token: imp.replaceVars(auth.param(auth.Bearer, "token")),
+		}
+	case "basic":
+		repr.Request.Auth = &configfile.AuthRepresentation{
+			Type:     "basic",
+			Username: imp.replaceVars(auth.param(auth.Basic, "username")),
+			Password: imp.replaceVars(auth.param(auth.Basic, "password")),
+		}
+	case "apikey":
+		if in := auth.param(auth.APIKey, "in"); in != "" && in != "header" {
+			imp.warnf("%s: api key in %s is not supported: ignored", name, in)
+			return
+		}
+		if repr.Request.Header == nil {
+			repr.Request.Header = map[string][]string{}
+		}
+		key := imp.replaceVars(auth.param(auth.APIKey, "key"))
+		repr.Request.Header[key] = []string{imp.replaceVars(auth.param(auth.APIKey, "value"))}
+	case "noauth":
+		// an inherited auth is removed by not extending its file:
+		// see postmanNode
+	default:
+		imp.warnf("%s: unsupported auth type %s: ignored", name, auth.Type)
+	}
+}
+
+// isNoAuth returns true if auth explicitly sets no auth.
+func (auth *postmanAuth) isNoAuth() bool {
+	return auth != nil && auth.Type == "noauth"
+}
+
+// postmanVarRgx matches a reference to a Postman variable.
+var postmanVarRgx = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// replaceVars replaces the references to Postman variables in s
+// with references to environment variables, defaulting to the value
+// of the collection variable if any. Literal references to environment
+// variables are escaped.
+func (imp *postmanImporter) replaceVars(s string) string {
+	s = escapeEnv(s)
+	return postmanVarRgx.ReplaceAllStringFunc(s, func(ref string) string {
+		name := postmanVarRgx.FindStringSubmatch(ref)[1]
+		if strings.HasPrefix(name, "$") {
+			imp.warnf("dynamic variable %s is not supported: kept as is", ref)
+			return ref
+		}
+		env := EnvName(name)
+		if def, ok := imp.variables[name]; ok && !strings.ContainsAny(def, "{}") {
+			if !imp.defaulted[name] {
+				imp.defaulted[name] = true
+				imp.warnf("collection variable %s is written as the default value of ${%s}: remove it if it is a secret", name, env)
+			}
+			return "${" + env + ":-" + def + "}"
+		}
+		return "${" + env + "}"
+	})
+}
+
+// EnvName returns the environment variable name for a Postman variable
+// name, converted to upper snake case: "baseUrl" becomes "BASE_URL".
+func EnvName(name string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r) && r < unicode.MaxASCII:
+			if prevLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			prevLower = false
+		case (unicode.IsLower(r) || unicode.IsDigit(r)) && r < unicode.MaxASCII:
+			b.WriteRune(unicode.ToUpper(r))
+			prevLower = true
+		default:
+			b.WriteByte('_')
+			prevLower = false
+		}
+	}
+	env := b.String()
+	if env == "" || unicode.IsDigit(rune(env[0])) {
+		env = "_" + env
+	}
+	return env
+}
+
+// hoistHeaders moves the headers shared by all the requests of a folder,
+// including its subfolders, to its base, recursively.
+func (n *postmanNode) hoistHeaders() {
+	reqs := n.allRequests()
+	if len(reqs) > 1 {
+		shared := map[string][]string{}
+		for key, values := range reqs[0].Request.Header {
+			shared[key] = values
+		}
+		for _, repr := range reqs[1:] {
+			for key, values := range shared {
+				if !equalStrings(repr.Request.Header[key], values) {
+					delete(shared, key)
+				}
+			}
+		}
+
+		for key, values := range shared {
+			if n.base.Request.Header == nil {
+				n.base.Request.Header = map[string][]string{}
+			}
+			n.base.Request.Header[key] = values
+			for _, repr := range reqs {
+				delete(repr.Request.Header, key)
+				if len(repr.Request.Header) == 0 {
+					repr.Request.Header = nil
+				}
+			}
+		}
+	}
+
+	for _, child := range n.children {
+		child.hoistHeaders()
+	}
+}
+
+// allRequests returns the representations of the requests of n
+// and its subfolders that extend its base file.
+func (n *postmanNode) allRequests() []*configfile.Representation {
+	reqs := make([]*configfile.Representation, 0, len(n.requests))
+	for i := range n.requests {
+		reqs = append(reqs, &n.requests[i].Representation)
+	}
+	for _, child := range n.children {
+		if !child.noAuth {
+			reqs = append(reqs, child.allRequests()...)
+		}
+	}
+	return reqs
+}
+
+// files returns the files of n and its subfolders, extending the base
+// file parentBase if not empty.
+func (n *postmanNode) files(ext, parentBase string) []File {
+	var files []File
+
+	if n.noAuth {
+		parentBase = ""
+	}
+
+	if !reflect.DeepEqual(n.base, configfile.Representation{}) {
+		base := File{Name: filepath.Join(n.dir, BaseFileName+ext), Representation: n.base}
+		setExtends(&base, parentBase)
+		files = append(files, base)
+		parentBase = base.Name
+	}
+
+	for _, f := range n.requests {
+		setExtends(&f, parentBase)
+		files = append(files, f)
+	}
+	files = append(files, n.noAuthRequests...)
+
+	for _, child := range n.children {
+		files = append(files, child.files(ext, parentBase)...)
+	}
+
+	return files
+}
+
+// setExtends sets the extends field of f to the path of base relative
+// to f, if base is not empty.
+func setExtends(f *File, base string) {
+	if base == "" {
+		return
+	}
+	rel, err := filepath.Rel(filepath.Dir(f.Name), base)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	f.Representation.Extends = &rel
+}
+
+// queryEscapeVars escapes s for a query string, except the references
+// to Postman variables.
+func queryEscapeVars(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range postmanVarRgx.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
+
+func hasHeader(header map[string][]string, key string) bool {
+	for k := range header {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,245 @@
+package importer_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benchttp/cli/internal/configfile"
+	"github.com/benchttp/cli/internal/importer"
+)
+
+const postmanInput = `{
+  "info": {
+    "name": "Shop",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "variable": [{"key": "baseUrl", "value": "https://shop.example.com"}],
+  "item": [
+    {
+      "name": "Health check",
+      "request": {"method": "GET", "url": "{{baseUrl}}/health"}
+    },
+    {
+      "name": "Products",
+      "item": [
+        {
+          "name": "List products",
+          "request": {
+            "method": "GET",
+            "url": {"raw": "{{baseUrl}}/products?page=1"},
+            "header": [
+              {"key": "Accept", "value": "application/json"},
+              {"key": "X-Debug", "value": "1", "disabled": true}
+            ]
+          }
+        },
+        {
+          "name": "Create product",
+          "event": [{"listen": "test"}],
+          "request": {
+            "method": "POST",
+            "url": "{{baseUrl}}/products",
+            "header": [{"key": "Accept", "value": "application/json"}],
+            "body": {
+              "mode": "raw",
+              "raw": "{\"name\":\"{{productName}}\"}",
+              "options": {"raw": {"language": "json"}}
+            }
+          }
+        },
+        {
+          "name": "Search",
+          "request": {
+            "method": "POST",
+            "url": "{{baseUrl}}/search",
+            "header": [{"key": "Accept", "value": "application/json"}],
+            "body": {
+              "mode": "urlencoded",
+              "urlencoded": [{"key": "q", "value": "red shoes {{size}}"}]
+            }
+          }
+        }
+      ]
+    }
+  ]
+}`
+
+func TestPostman(t *testing.T) {
+	t.Run("convert collection to extends hierarchy", func(t *testing.T) {
+		files, warnings, err := importer.Postman(strings.NewReader(postmanInput), importer.PostmanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(warnings) != 2 {
+			t.Errorf("exp 2 warnings for the script and the default of baseUrl, got %v", warnings)
+		}
+
+		expNames := []string{
+			"base.yml",
+			"health-check.yml",
+			filepath.Join("products", "base.yml"),
+			filepath.Join("products", "list-products.yml"),
+			filepath.Join("products", "create-product.yml"),
+			filepath.Join("products", "search.yml"),
+		}
+		if got := fileNames(files); !reflect.DeepEqual(got, expNames) {
+			t.Fatalf("bad file names:\nexp %v\ngot %v", expNames, got)
+		}
+
+		rootBase := configfile.Representation{}
+		rootBase.Request.Auth = &configfile.AuthRepresentation{Type: "bearer", Token: "${TOKEN}"}
+		if got := files[0].Representation; !reflect.DeepEqual(got, rootBase) {
+			t.Errorf("bad root base:\nexp %#v\ngot %#v", rootBase, got)
+		}
+
+		folderBase := configfile.Representation{Extends: ptr("../base.yml")}
+		folderBase.Request.Header = map[string][]string{"Accept": {"application/json"}}
+		if got := files[2].Representation; !reflect.DeepEqual(got, folderBase) {
+			t.Errorf("bad folder base:\nexp %#v\ngot %#v", folderBase, got)
+		}
+
+		list := configfile.Representation{Extends: ptr("base.yml")}
+		list.Request.Method = ptr("GET")
+		list.Request.URL = ptr("${BASE_URL:-https://shop.example.com}/products?page=1")
+		if got := files[3].Representation; !reflect.DeepEqual(got, list) {
+			t.Errorf("bad request:\nexp %#v\ngot %#v", list, got)
+		}
+
+		create := files[4].Representation.Request
+		if create.Body == nil || create.Body.Content != `{"name":"${PRODUCT_NAME}"}` {
+			t.Errorf("bad raw body: %#v", create.Body)
+		}
+		if got := create.Header["Content-Type"]; !reflect.DeepEqual(got, []string{"application/json"}) {
+			t.Errorf("bad content type: %v", got)
+		}
+
+		search := files[5].Representation.Request
+		if search.Body == nil || search.Body.Content != "q=red+shoes+${SIZE}" {
+			t.Errorf("bad urlencoded body: %#v", search.Body)
+		}
+	})
+
+	t.Run("generated files are valid config files", func(t *testing.T) {
+		t.Setenv("TOKEN", "secret")
+		t.Setenv("PRODUCT_NAME", "shoe")
+		t.Setenv("SIZE", "42")
+
+		files, _, err := importer.Postman(strings.NewReader(postmanInput), importer.PostmanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		for _, f := range files {
+			if err := configfile.Write(filepath.Join(dir, f.Name), f.Representation); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		cfg, err := configfile.Parse(filepath.Join(dir, "products", "create-product.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.Request.URL.String(); got != "https://shop.example.com/products" {
+			t.Errorf("bad url: %s", got)
+		}
+		if got := cfg.Request.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("bad inherited auth: %q", got)
+		}
+		if got := cfg.Request.Header["Accept"]; !reflect.DeepEqual(got, []string{"application/json"}) {
+			t.Errorf("bad inherited header: %v", got)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "products", "base.yml")); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("do not inherit auth in requests and folders without auth", func(t *testing.T) {
+		in := `{
+			"info": {"name": "Auth", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+			"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "s3cr3t"}]},
+			"item": [
+				{"name": "Private", "request": {"method": "GET", "url": "https://a.b/private"}},
+				{"name": "Public", "request": {"method": "GET", "url": "https://a.b/public", "auth": {"type": "noauth"}}},
+				{"name": "Open", "auth": {"type": "noauth"}, "item": [
+					{"name": "Docs", "request": {"method": "GET", "url": "https://a.b/docs"}}
+				]}
+			]
+		}`
+		files, _, err := importer.Postman(strings.NewReader(in), importer.PostmanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dir := t.TempDir()
+		for _, f := range files {
+			if err := configfile.Write(filepath.Join(dir, f.Name), f.Representation); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for name, exp := range map[string]string{
+			"private.yml":                     "Bearer s3cr3t",
+			"public.yml":                      "",
+			filepath.Join("open", "docs.yml"): "",
+		} {
+			cfg, err := configfile.Parse(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.Request.Header.Get("Authorization"); got != exp {
+				t.Errorf("%s: exp auth %q, got %q", name, exp, got)
+			}
+		}
+	})
+
+	t.Run("map variable names to env names", func(t *testing.T) {
+		for in, exp := range map[string]string{
+			"baseUrl":    "BASE_URL",
+			"api-key":    "API_KEY",
+			"user.id":    "USER_ID",
+			"TOKEN":      "TOKEN",
+			"2faCode":    "_2FA_CODE",
+			"clientID_2": "CLIENT_ID_2",
+		} {
+			if got := importer.EnvName(in); got != exp {
+				t.Errorf("%s: exp %s, got %s", in, exp, got)
+			}
+		}
+	})
+
+	t.Run("escape references to environment variables", func(t *testing.T) {
+		in := `{
+			"info": {"name": "Env", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+			"item": [{"name": "Template", "request": {
+				"method": "POST",
+				"url": "https://api.example.com/${IMPORT_TEST_PATH}",
+				"header": [{"key": "x-tpl", "value": "${IMPORT_TEST_USER:-me}"}],
+				"body": {"mode": "raw", "raw": "{\"a\":\"${IMPORT_TEST_A}\"}"}
+			}}]
+		}`
+		files, _, err := importer.Postman(strings.NewReader(in), importer.PostmanOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cfg := parseImported(t, files, files[len(files)-1].Name)
+		assertLiteralEnvRefs(t, cfg, "/${IMPORT_TEST_PATH}", "x-tpl", "${IMPORT_TEST_USER:-me}", `{"a":"${IMPORT_TEST_A}"}`)
+	})
+
+	t.Run("return ErrPostman for unsupported input", func(t *testing.T) {
+		for _, in := range []string{
+			`{"info": {"schema": "https://schema.getpostman.com/json/collection/v1.0.0/"}}`,
+			"{",
+		} {
+			_, _, err := importer.Postman(strings.NewReader(in), importer.PostmanOptions{})
+			if !errors.Is(err, importer.ErrPostman) {
+				t.Errorf("%q: exp ErrPostman, got %v", in, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+// ErrShellSyntax signals a command line that cannot be split into words.
+var ErrShellSyntax = errors.New("invalid command line")
+
+// splitWords splits a POSIX shell command line into words, handling
+// single quotes, double quotes, ANSI-C quotes ($'...'), backslash escapes
+// and line continuations. Other shell features such as variables,
+// globs or pipes are not interpreted.
+func splitWords(line string) ([]string, error) { //nolint:gocognit // acceptable complexity for a tokenizer
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+	)
+
+	flush := func() {
+		if inWord {
+			words = append(words, current.String())
+			current.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+
+		case c == '\\':
+			if i+1 >= len(line) {
+				return nil, errorutil.WithDetails(ErrShellSyntax, "trailing backslash")
+			}
+			i++
+			if line[i] == '\n' { // line continuation
+				continue
+			}
+			if line[i] == '\r' && i+1 < len(line) && line[i+1] == '\n' {
+				i++
+				continue
+			}
+			current.WriteByte(line[i])
+			inWord = true
+
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end == -1 {
+				return nil, errorutil.WithDetails(ErrShellSyntax, "unterminated single quote")
+			}
+			current.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+
+		case c == '$' && i+1 < len(line) && line[i+1] == '\'':
+			s, n, err := readANSIQuoted(line[i+2:])
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(s)
+			i += n + 2
+			inWord = true
+
+		case c == '"':
+			s, n, err := readDoubleQuoted(line[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(s)
+			i += n + 1
+			inWord = true
+
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+
+	return words, nil
+}
+
+// readDoubleQuoted reads a double-quoted string until its closing quote.
+// It returns the unquoted string and the index of the closing quote in s.
+func readDoubleQuoted(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return b.String(), i, nil
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) != -1 {
+				i++
+				if s[i] != '\n' {
+					b.WriteByte(s[i])
+				}
+				continue
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errorutil.WithDetails(ErrShellSyntax, "unterminated double quote")
+}
+
+// readANSIQuoted reads an ANSI-C quoted string ($'...') until its closing
+// quote. It returns the unquoted string and the index of the closing
+// quote in s.
+func readANSIQuoted(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return b.String(), i, nil
+		}
+		if c != '\\' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch e := s[i]; e {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+			end := i + 1
+			for end < len(s) && end < i+1+size && isHexDigit(s[end]) {
+				end++
+			}
+			code, err := strconv.ParseUint(s[i+1:end], 16, 32)
+			if err != nil {
+				return "", 0, errorutil.WithDetails(ErrShellSyntax, "invalid escape sequence")
+			}
+			if e == 'x' {
+				b.WriteByte(byte(code))
+			} else {
+				b.WriteRune(rune(code))
+			}
+			i = end - 1
+		default: // \\ \' \" and unknown sequences
+			b.WriteByte(e)
+		}
+	}
+	return "", 0, errorutil.WithDetails(ErrShellSyntax, "unterminated ANSI-C quote")
+}
+
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// Mask is the placeholder written in place of hidden values.
+const Mask = "****"
+
+// minMaskedLen is the minimum length of a value to be masked by
+// MaskValues. Shorter values are unlikely to be secrets and masking
+// them would garble the output.
+const minMaskedLen = 4
+
+// MaskValues returns s with every occurrence of values replaced by Mask.
+func MaskValues(s string, values []string) string {
+	if len(values) == 0 {
+		return s
+	}
+
+	// mask longest values first in case a value contains another one
+	sorted := append([]string{}, values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	for _, v := range sorted {
+		if len(v) < minMaskedLen {
+			continue
+		}
+		s = strings.ReplaceAll(s, v, Mask)
+	}
+	return s
+}
+
+// MaskingWriter is an io.Writer that wraps an input writer and replaces
+// every occurrence of the given values with Mask before writing.
+type MaskingWriter struct {
+	Writer io.Writer
+	Values []string
+}
+
+// Write writes b to the underlying writer with the values masked.
+// It returns len(b) on success, regardless of the length of the actual
+// written output.
+func (w MaskingWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(w.Writer, MaskValues(string(b), w.Values)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
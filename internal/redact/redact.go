@@ -0,0 +1,224 @@
+// Package redact hides sensitive values, such as credentials, from
+// everything the CLI renders or writes out.
+package redact
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/output"
+)
+
+// DefaultHeaders is the list of header names that are always redacted.
+var DefaultHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"X-Csrf-Token",
+	"X-Xsrf-Token",
+}
+
+// Redactor replaces sensitive values with output.Mask. Values are
+// identified by their location (header names, query parameters, body
+// JSON paths) or by their literal content.
+//
+// A nil *Redactor is valid and redacts nothing.
+type Redactor struct {
+	rules  Rules
+	values []string
+}
+
+// New returns a *Redactor that redacts DefaultHeaders and the locations
+// described by the given rules.
+func New(rules Rules) *Redactor {
+	rules.Headers = append(append([]string{}, DefaultHeaders...), rules.Headers...)
+	return &Redactor{rules: rules}
+}
+
+// AddValues registers literal values to be redacted wherever they occur.
+func (r *Redactor) AddValues(values ...string) {
+	if r == nil {
+		return
+	}
+	r.values = append(r.values, values...)
+}
+
+// Values returns the literal values registered via AddValues.
+func (r *Redactor) Values() []string {
+	if r == nil {
+		return nil
+	}
+	return r.values
+}
+
+// Writer returns an io.Writer that writes to w with the registered
+// literal values redacted.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+	return output.MaskingWriter{Writer: w, Values: r.values}
+}
+
+// String returns s with the registered literal values redacted.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	return output.MaskValues(s, r.values)
+}
+
+// Header returns a copy of h with the values of sensitive headers
+// redacted.
+func (r *Redactor) Header(h http.Header) http.Header {
+	if r == nil || h == nil {
+		return h
+	}
+	redacted := make(http.Header, len(h))
+	for key, values := range h {
+		masked := make([]string, len(values))
+		for i, v := range values {
+			if r.isSensitiveHeader(key) {
+				masked[i] = output.Mask
+			} else {
+				masked[i] = r.String(v)
+			}
+		}
+		redacted[key] = masked
+	}
+	return redacted
+}
+
+// URL returns a copy of u with its password and the values of sensitive
+// query parameters redacted. The order of the query parameters is kept.
+func (r *Redactor) URL(u *url.URL) *url.URL {
+	if r == nil || u == nil {
+		return u
+	}
+
+	redacted := *u
+	if _, hasPassword := u.User.Password(); hasPassword {
+		redacted.User = url.UserPassword(u.User.Username(), output.Mask)
+	}
+
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		for i, pair := range pairs {
+			rawKey := strings.SplitN(pair, "=", 2)[0]
+			key, err := url.QueryUnescape(rawKey)
+			if err != nil {
+				key = rawKey
+			}
+			if r.isSensitiveQueryParam(key) {
+				pairs[i] = rawKey + "=" + output.Mask
+			}
+		}
+		redacted.RawQuery = strings.Join(pairs, "&")
+	}
+
+	// catch registered values located anywhere in the url
+	if s := redacted.String(); r.String(s) != s {
+		if masked, err := url.Parse(r.String(s)); err == nil {
+			return masked
+		}
+	}
+
+	return &redacted
+}
+
+// Body returns a copy of b with the registered values redacted and,
+// if b is a JSON document, the values located at sensitive paths.
+func (r *Redactor) Body(b []byte) []byte {
+	if r == nil || len(b) == 0 {
+		return b
+	}
+
+	if len(r.rules.Body) != 0 {
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err == nil {
+			for _, p := range r.rules.Body {
+				doc = maskPath(doc, splitPath(p))
+			}
+			if masked, err := json.Marshal(doc); err == nil {
+				b = masked
+			}
+		}
+	}
+
+	return []byte(r.String(string(b)))
+}
+
+// Config returns a copy of cfg with sensitive request values redacted.
+func (r *Redactor) Config(cfg runner.Config) runner.Config {
+	if r == nil {
+		return cfg
+	}
+	cfg.Request.URL = r.URL(cfg.Request.URL)
+	cfg.Request.Header = r.Header(cfg.Request.Header)
+	cfg.Request.Body.Content = r.Body(cfg.Request.Body.Content)
+	return cfg
+}
+
+// Report returns a copy of rep with sensitive values redacted from its
+// config and its failure reasons, that may contain the request URL.
+func (r *Redactor) Report(rep *runner.Report) *runner.Report {
+	if r == nil || rep == nil {
+		return rep
+	}
+
+	redacted := *rep
+	cfg := rep.Metadata.Config
+	redacted.Metadata.Config = r.Config(cfg)
+
+	if failures := rep.Metrics.RequestFailures; len(failures) != 0 {
+		var rawURL, maskedURL string
+		if cfg.Request.URL != nil {
+			rawURL = cfg.Request.URL.String()
+			maskedURL = redacted.Metadata.Config.Request.URL.String()
+		}
+
+		redacted.Metrics.RequestFailures = make([]struct{ Reason string }, len(failures))
+		for i, f := range failures {
+			reason := f.Reason
+			if rawURL != "" {
+				reason = strings.ReplaceAll(reason, rawURL, maskedURL)
+			}
+			redacted.Metrics.RequestFailures[i].Reason = r.String(reason)
+		}
+	}
+
+	return &redacted
+}
+
+func (r *Redactor) isSensitiveHeader(name string) bool {
+	return matchAny(r.rules.Headers, name, true)
+}
+
+func (r *Redactor) isSensitiveQueryParam(name string) bool {
+	return matchAny(r.rules.Query, name, false)
+}
+
+// matchAny returns true if name matches any of the glob patterns.
+func matchAny(patterns []string, name string, ignoreCase bool) bool {
+	if ignoreCase {
+		name = strings.ToLower(name)
+	}
+	for _, pattern := range patterns {
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/benchttp/cli/internal/output"
+)
+
+// Rules describes the locations of sensitive values, in addition
+// to DefaultHeaders.
+type Rules struct {
+	// Headers is a list of case-insensitive glob patterns matching
+	// header names, e.g. "X-Secret-*".
+	Headers []string
+	// Query is a list of glob patterns matching query parameter names,
+	// e.g. "*token".
+	Query []string
+	// Body is a list of dot-separated paths in a JSON body,
+	// e.g. "user.password". A "*" segment matches any key or index,
+	// and arrays are traversed implicitly.
+	Body []string
+}
+
+// String returns a string representation of the rules.
+func (rules *Rules) String() string {
+	if rules == nil {
+		return ""
+	}
+	var all []string
+	for _, set := range []struct {
+		kind     string
+		patterns []string
+	}{
+		{"header", rules.Headers},
+		{"query", rules.Query},
+		{"body", rules.Body},
+	} {
+		for _, p := range set.patterns {
+			all = append(all, set.kind+":"+p)
+		}
+	}
+	return strings.Join(all, ",")
+}
+
+// Set reads input string in format "<header|query|body>:<pattern>"
+// and appends the pattern to the corresponding rules.
+// It implements flag.Value.
+func (rules *Rules) Set(raw string) error {
+	split := strings.SplitN(raw, ":", 2)
+	if len(split) != 2 || split[1] == "" {
+		return fmt.Errorf(`expect format "<header|query|body>:<pattern>", got "%s"`, raw)
+	}
+
+	kind, pattern := split[0], split[1]
+	switch kind {
+	case "header":
+		rules.Headers = append(rules.Headers, pattern)
+	case "query":
+		rules.Query = append(rules.Query, pattern)
+	case "body":
+		rules.Body = append(rules.Body, pattern)
+	default:
+		return fmt.Errorf(`unsupported kind: %s (want "header", "query" or "body")`, kind)
+	}
+	return nil
+}
+
+// splitPath splits a dot-separated JSON path into its segments.
+// A leading "$." is ignored.
+func splitPath(p string) []string {
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "$"), ".")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, ".")
+}
+
+// maskPath replaces the values located at path in the decoded JSON
+// document doc with output.Mask, and returns the resulting document.
+func maskPath(doc interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return output.Mask
+	}
+
+	segment, next := path[0], path[1:]
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			if segment == "*" || segment == key {
+				node[key] = maskPath(child, next)
+			}
+		}
+	case []interface{}:
+		index, err := strconv.Atoi(segment)
+		for i, child := range node {
+			switch {
+			case segment == "*":
+				node[i] = maskPath(child, next)
+			case err == nil:
+				if i == index {
+					node[i] = maskPath(child, next)
+				}
+			default: // implicit traversal: apply the same path to each item
+				node[i] = maskPath(child, path)
+			}
+		}
+	}
+
+	return doc
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// BaselineMetric is a metric compared to its baseline value,
+// as rendered by BaselineComparisonString.
+type BaselineMetric struct {
+	Name string
+
+	// Baseline and Current are the values of the metric,
+	// ints or time.Durations.
+	Baseline, Current runner.MetricsValue
+
+	// Tolerance is the regression allowed for the metric, e.g. "10%",
+	// empty if it has none.
+	Tolerance string
+
+	// Regressed is true if the metric regressed beyond its tolerance.
+	Regressed bool
+}
+
+// BaselineComparison writes the table of BaselineComparisonString to w.
+func BaselineComparison(w io.Writer, baseline string, metrics []BaselineMetric) (int, error) {
+	return w.Write([]byte(BaselineComparisonString(baseline, metrics)))
+}
+
+// BaselineComparisonString returns a table of the metrics of a report
+// compared to the ones of the given baseline, with their relative
+// difference and the result of the ones with a tolerance.
+func BaselineComparisonString(baseline string, metrics []BaselineMetric) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold("→ Baseline (" + baseline + ")"))
+	b.WriteString("\n")
+
+	header := []string{"Metric", "Baseline", "Current", "Delta", "Tolerance", "Result"}
+	rows := make([][]cell, len(metrics))
+	for i, m := range metrics {
+		result := cell{text: "-"}
+		switch {
+		case m.Tolerance == "":
+		case m.Regressed:
+			result = cell{text: "FAIL", style: ansi.Red}
+		default:
+			result = cell{text: "PASS", style: ansi.Green}
+		}
+		rows[i] = []cell{
+			{text: m.Name},
+			{text: formatValue(m.Baseline)},
+			{text: formatValue(m.Current)},
+			{text: formatValueDelta(m.Baseline, m.Current)},
+			{text: orDash(m.Tolerance)},
+			result,
+		}
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// formatValue formats a metric value, durations in milliseconds.
+func formatValue(v runner.MetricsValue) string {
+	if d, ok := v.(time.Duration); ok {
+		return formatFloatMs(float64(d))
+	}
+	return fmt.Sprint(v)
+}
+
+// formatValueDelta returns the relative difference from before to
+// after, both ints or both durations, as a signed percentage.
+func formatValueDelta(before, after runner.MetricsValue) string {
+	switch before := before.(type) {
+	case time.Duration:
+		if after, ok := after.(time.Duration); ok {
+			return formatDelta(before, after)
+		}
+	case int:
+		if after, ok := after.(int); ok {
+			return formatDelta(time.Duration(before), time.Duration(after))
+		}
+	}
+	return "-"
+}
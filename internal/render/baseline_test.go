@@ -0,0 +1,29 @@
+package render_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func TestBaselineComparisonString(t *testing.T) {
+	metrics := []render.BaselineMetric{
+		{Name: "RequestCount", Baseline: 10, Current: 10},
+		{Name: "ResponseTimes.Mean", Baseline: 10 * time.Millisecond, Current: 12 * time.Millisecond, Tolerance: "10%", Regressed: true},
+		{Name: "ResponseTimes.Max", Baseline: 20 * time.Millisecond, Current: 18 * time.Millisecond, Tolerance: "5ms"},
+	}
+
+	exp := ansi.Bold("→ Baseline (baseline.json)") + "\n" +
+		ansi.Bold("Metric") + "              " + ansi.Bold("Baseline") + "  " + ansi.Bold("Current") + "  " +
+		ansi.Bold("Delta") + "   " + ansi.Bold("Tolerance") + "  " + ansi.Bold("Result") + "\n" +
+		"RequestCount        10        10       +0.0%   -          -\n" +
+		"ResponseTimes.Mean  10.00ms   12.00ms  +20.0%  10%        " + ansi.Red("FAIL") + "\n" +
+		"ResponseTimes.Max   20.00ms   18.00ms  -10.0%  5ms        " + ansi.Green("PASS") + "\n" +
+		"\n"
+
+	if got := render.BaselineComparisonString("baseline.json", metrics); got != exp {
+		t.Errorf("\nexp:\n%s\ngot:\n%s", exp, got)
+	}
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// Verdict is the conclusion of a statistical comparison.
+type Verdict int
+
+const (
+	// NoDifference signals no significant difference.
+	NoDifference Verdict = iota
+	// Improvement signals significantly faster responses.
+	Improvement
+	// Regression signals significantly slower responses.
+	Regression
+)
+
+// Comparison is a statistical comparison of the response times of two
+// benchmarks, as rendered by ComparisonReportString.
+type Comparison struct {
+	// Method is the name of the statistical test.
+	Method string
+
+	// Confidence is the confidence level of the test, e.g. 0.95.
+	Confidence float64
+
+	// Before and After name the compared benchmarks.
+	Before, After string
+
+	// Metrics lists the metrics compared side by side.
+	Metrics []ComparedMetric
+
+	// Result describes the outcome of the test, e.g. its p-value.
+	Result string
+
+	Verdict Verdict
+}
+
+// ComparedMetric is a metric of the compared benchmarks.
+type ComparedMetric struct {
+	Name          string
+	Before, After time.Duration
+}
+
+// ComparisonReport writes the report of ComparisonReportString to w.
+func ComparisonReport(w io.Writer, c Comparison) (int, error) {
+	return w.Write([]byte(ComparisonReportString(c)))
+}
+
+// ComparisonReportString returns a table of the metrics of the compared
+// benchmarks and their relative difference, followed by the outcome
+// of the statistical test and its verdict.
+func ComparisonReportString(c Comparison) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold(fmt.Sprintf("→ Comparison (%s, %s confidence)", c.Method, formatPercent(c.Confidence))))
+	b.WriteString("\n")
+
+	header := []string{"Metric", c.Before, c.After, "Delta"}
+	rows := make([][]cell, len(c.Metrics))
+	for i, m := range c.Metrics {
+		rows[i] = []cell{
+			{text: m.Name},
+			{text: formatFloatMs(float64(m.Before))},
+			{text: formatFloatMs(float64(m.After))},
+			{text: formatDelta(m.Before, m.After)},
+		}
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	b.WriteString(c.Result)
+	b.WriteString("\n")
+	switch c.Verdict {
+	case Regression:
+		b.WriteString(ansi.Red("REGRESSION") + ": " + c.After + " is significantly slower")
+	case Improvement:
+		b.WriteString(ansi.Green("IMPROVEMENT") + ": " + c.After + " is significantly faster")
+	default:
+		b.WriteString(ansi.Grey("NO SIGNIFICANT DIFFERENCE"))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// formatDelta returns the relative difference from before to after
+// as a signed percentage.
+func formatDelta(before, after time.Duration) string {
+	if before == 0 {
+		return "-"
+	}
+	delta := float64(after-before) / float64(before) * 100
+	return fmt.Sprintf("%+.1f%%", delta)
+}
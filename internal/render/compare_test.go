@@ -0,0 +1,46 @@
+package render_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func TestComparisonReportString(t *testing.T) {
+	c := render.Comparison{
+		Method:     "Mann–Whitney U",
+		Confidence: 0.95,
+		Before:     "before.json",
+		After:      "after.json",
+		Metrics: []render.ComparedMetric{
+			{Name: "p50", Before: 10 * time.Millisecond, After: 12 * time.Millisecond},
+			{Name: "p99", Before: 0, After: 20 * time.Millisecond},
+		},
+		Result: "p-value: 0.0012",
+	}
+
+	table := ansi.Bold("→ Comparison (Mann–Whitney U, 95% confidence)") + "\n" +
+		ansi.Bold("Metric") + "  " + ansi.Bold("before.json") + "  " + ansi.Bold("after.json") + "  " + ansi.Bold("Delta") + "\n" +
+		"p50     10.00ms      12.00ms     +20.0%\n" +
+		"p99     0.00ms       20.00ms     -\n" +
+		"\n" +
+		"p-value: 0.0012\n"
+
+	testcases := []struct {
+		verdict render.Verdict
+		exp     string
+	}{
+		{verdict: render.NoDifference, exp: ansi.Grey("NO SIGNIFICANT DIFFERENCE")},
+		{verdict: render.Regression, exp: ansi.Red("REGRESSION") + ": after.json is significantly slower"},
+		{verdict: render.Improvement, exp: ansi.Green("IMPROVEMENT") + ": after.json is significantly faster"},
+	}
+
+	for _, tc := range testcases {
+		c.Verdict = tc.verdict
+		if got, exp := render.ComparisonReportString(c), table+tc.exp+"\n"; got != exp {
+			t.Errorf("\nexp:\n%s\ngot:\n%s", exp, got)
+		}
+	}
+}
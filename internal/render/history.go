@@ -0,0 +1,126 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// historyTimeLayout is the layout of the times of the history entries.
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+// HistoryEntry is a report of the history, as rendered by HistoryList
+// and Trend.
+type HistoryEntry struct {
+	ID         string
+	Name       string
+	ConfigHash string
+	Commit     string
+	Time       time.Time
+	Report     *runner.Report
+}
+
+// HistoryList writes the table of HistoryListString to w.
+func HistoryList(w io.Writer, entries []HistoryEntry) (int, error) {
+	return w.Write([]byte(HistoryListString(entries)))
+}
+
+// HistoryListString returns a table of the history entries, one row
+// per entry.
+func HistoryListString(entries []HistoryEntry) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold("→ History"))
+	b.WriteString("\n")
+
+	header := []string{"ID", "Time", "Benchmark", "Config", "Commit", "Requests", "Mean", "Tests"}
+	rows := make([][]cell, len(entries))
+	for i, e := range entries {
+		rows[i] = []cell{
+			{text: e.ID},
+			{text: e.Time.Local().Format(historyTimeLayout)},
+			{text: e.Name},
+			{text: e.ConfigHash},
+			{text: orDash(e.Commit)},
+			{text: strconv.Itoa(len(e.Report.Metrics.Records))},
+			{text: formatMs(e.Report.Metrics.ResponseTimes.Mean)},
+			testsCell(e.Report.Tests),
+		}
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// Trend writes the table of TrendString to w.
+func Trend(w io.Writer, entries []HistoryEntry) (int, error) {
+	return w.Write([]byte(TrendString(entries)))
+}
+
+// TrendString returns a table of the summary metrics of the history
+// entries of a same config, from the oldest to the most recent, with
+// the variation of the mean response time from one entry to the next.
+func TrendString(entries []HistoryEntry) string {
+	var b strings.Builder
+
+	title := "→ Trend"
+	if len(entries) != 0 {
+		title += fmt.Sprintf(": %s (%s)", entries[0].ConfigHash, entries[0].Name)
+	}
+	b.WriteString(ansi.Bold(title))
+	b.WriteString("\n")
+
+	header := []string{"Time", "Commit", "Requests", "Errors", "Min", "Max", "Mean", "Δ Mean", "Duration", "Tests"}
+	rows := make([][]cell, len(entries))
+	for i, e := range entries {
+		m := e.Report.Metrics
+		delta := cell{text: "-"}
+		if i != 0 {
+			delta = deltaCell(entries[i-1].Report.Metrics.ResponseTimes.Mean, m.ResponseTimes.Mean)
+		}
+		rows[i] = []cell{
+			{text: e.Time.Local().Format(historyTimeLayout)},
+			{text: orDash(e.Commit)},
+			{text: strconv.Itoa(len(m.Records))},
+			{text: strconv.Itoa(len(m.RequestFailures))},
+			{text: formatMs(m.ResponseTimes.Min)},
+			{text: formatMs(m.ResponseTimes.Max)},
+			{text: formatMs(m.ResponseTimes.Mean)},
+			delta,
+			{text: formatMs(e.Report.Metadata.TotalDuration)},
+			testsCell(e.Report.Tests),
+		}
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// deltaCell returns the cell of the relative difference from before
+// to after, red if after is slower and green if faster.
+func deltaCell(before, after time.Duration) cell {
+	c := cell{text: formatDelta(before, after)}
+	switch {
+	case before == 0 || after == before:
+	case after > before:
+		c.style = ansi.Red
+	default:
+		c.style = ansi.Green
+	}
+	return c
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
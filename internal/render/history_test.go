@@ -0,0 +1,56 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func historyStub() []render.HistoryEntry {
+	t0 := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	rep := func(mean time.Duration) *runner.Report {
+		metrics, duration := metricsStub()
+		metrics.ResponseTimes.Mean = mean
+		return &runner.Report{Metrics: metrics, Metadata: runner.ReportMetadata{TotalDuration: duration}}
+	}
+	return []render.HistoryEntry{
+		{ID: "id1", Name: "users.yml", ConfigHash: "abc", Commit: "1234567", Time: t0, Report: rep(5 * time.Second)},
+		{ID: "id2", Name: "users.yml", ConfigHash: "abc", Time: t0.Add(time.Hour), Report: rep(6 * time.Second)},
+		{ID: "id3", Name: "users.yml", ConfigHash: "abc", Time: t0.Add(2 * time.Hour), Report: rep(3 * time.Second)},
+	}
+}
+
+func TestHistoryListString(t *testing.T) {
+	entries := historyStub()
+	got := render.HistoryListString(entries[:2])
+
+	for _, exp := range []string{
+		ansi.Bold("→ History") + "\n",
+		"id1  " + entries[0].Time.Local().Format("2006-01-02 15:04:05") + "  users.yml  abc     1234567  3         5000ms  -\n",
+		"id2  " + entries[1].Time.Local().Format("2006-01-02 15:04:05") + "  users.yml  abc     -        3         6000ms  -\n",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("missing:\n%q\nin:\n%q", exp, got)
+		}
+	}
+}
+
+func TestTrendString(t *testing.T) {
+	got := render.TrendString(historyStub())
+
+	for _, exp := range []string{
+		ansi.Bold("→ Trend: abc (users.yml)") + "\n",
+		"5000ms  -       ",
+		"6000ms  " + ansi.Red("+20.0%") + "  ",
+		"3000ms  " + ansi.Green("-50.0%") + "  ",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("missing:\n%q\nin:\n%q", exp, got)
+		}
+	}
+}
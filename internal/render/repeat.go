@@ -0,0 +1,76 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// Estimate is the estimate of a summary metric over repeated runs,
+// as rendered by RepeatSummary.
+type Estimate struct {
+	// Name is the name of the metric, as in the report summary.
+	Name string
+
+	// Duration is true if the values are durations in nanoseconds.
+	Duration bool
+
+	Mean, StdDev float64
+
+	// Low and High are the bounds of the confidence interval.
+	Low, High float64
+}
+
+// RepeatSummary writes the summary of RepeatSummaryString to w.
+func RepeatSummary(w io.Writer, rep *runner.Report, runs int, confidence float64, estimates []Estimate) (int, error) {
+	return w.Write([]byte(RepeatSummaryString(rep, runs, confidence, estimates)))
+}
+
+// RepeatSummaryString returns the summary of repeated runs of the
+// benchmark of rep: the mean, standard deviation and confidence interval
+// of each summary metric.
+func RepeatSummaryString(rep *runner.Report, runs int, confidence float64, estimates []Estimate) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold(fmt.Sprintf("→ Summary (%d runs)", runs)))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%-18s %v\n", "Endpoint", rep.Metadata.Config.Request.URL)
+
+	header := []string{"Metric", "Mean", "StdDev", formatPercent(confidence) + " CI"}
+	rows := make([][]cell, len(estimates))
+	for i, e := range estimates {
+		format := formatCount
+		if e.Duration {
+			format = formatFloatMs
+		}
+		rows[i] = []cell{
+			{text: e.Name},
+			{text: format(e.Mean)},
+			{text: format(e.StdDev)},
+			{text: "[" + format(e.Low) + ", " + format(e.High) + "]"},
+		}
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func formatCount(v float64) string {
+	return fmt.Sprintf("%.1f", v)
+}
+
+func formatFloatMs(ns float64) string {
+	return fmt.Sprintf("%.2fms", ns/1e6)
+}
+
+// formatPercent formats ratio as a percentage, rounded to the tenth
+// to hide floating point errors.
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%g%%", math.Round(ratio*1000)/10)
+}
@@ -0,0 +1,30 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func TestRepeatSummaryString(t *testing.T) {
+	rep := &runner.Report{Metadata: runner.ReportMetadata{Config: configStub()}}
+
+	got := render.RepeatSummaryString(rep, 5, 0.95, []render.Estimate{
+		{Name: "Requests", Mean: 100, StdDev: 0, Low: 100, High: 100},
+		{Name: "Mean response time", Duration: true, Mean: 12.5e6, StdDev: 1.25e6, Low: 11e6, High: 14e6},
+	})
+
+	exp := ansi.Bold("→ Summary (5 runs)") + "\n" +
+		"Endpoint           https://a.b.com\n" +
+		ansi.Bold("Metric") + "              " + ansi.Bold("Mean") + "     " + ansi.Bold("StdDev") + "  " + ansi.Bold("95% CI") + "\n" +
+		"Requests            100.0    0.0     [100.0, 100.0]\n" +
+		"Mean response time  12.50ms  1.25ms  [11.00ms, 14.00ms]\n" +
+		"\n"
+
+	if got != exp {
+		t.Errorf("\nexp:\n%s\ngot:\n%s", exp, got)
+	}
+}
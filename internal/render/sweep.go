@@ -0,0 +1,167 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// chartWidth is the width of the longest bar of the sweep charts.
+const chartWidth = 40
+
+// SweepPoint holds the metrics of the benchmark of one value
+// of a swept parameter, as rendered by Sweep.
+type SweepPoint struct {
+	// Value is the formatted value of the parameter.
+	Value string
+
+	// Failed is true if the benchmark could not run.
+	Failed bool
+
+	// Knee is true if the point is the knee of the sweep.
+	Knee bool
+
+	Requests      int
+	Errors        int
+	Throughput    float64
+	P50, P90, P99 time.Duration
+}
+
+// Sweep writes the table and charts of SweepString to w.
+func Sweep(w io.Writer, param string, points []SweepPoint) (int, error) {
+	return w.Write([]byte(SweepString(param, points)))
+}
+
+// SweepString returns a table of the throughput and latency percentiles
+// of each value of the swept parameter, followed by bar charts of them.
+// The knee, if any, is highlighted.
+func SweepString(param string, points []SweepPoint) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold("→ Sweep: " + param))
+	b.WriteString("\n")
+
+	header := []string{param, "Requests", "Errors", "Throughput", "p50", "p90", "p99"}
+	rows := make([][]cell, 0, len(points))
+	for _, p := range points {
+		if p.Failed {
+			rows = append(rows, []cell{
+				{text: p.Value}, {text: "-"}, {text: "-"}, {text: "-"},
+				{text: "-"}, {text: "-"}, {text: "ERROR", style: ansi.Red},
+			})
+			continue
+		}
+		value := cell{text: p.Value}
+		if p.Knee {
+			value = cell{text: p.Value + " (knee)", style: ansi.Yellow}
+		}
+		rows = append(rows, []cell{
+			value,
+			{text: strconv.Itoa(p.Requests)},
+			{text: strconv.Itoa(p.Errors)},
+			{text: formatThroughput(p.Throughput)},
+			{text: formatMs(p.P50)},
+			{text: formatMs(p.P90)},
+			{text: formatMs(p.P99)},
+		})
+	}
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	b.WriteString(ansi.Bold("→ Throughput"))
+	b.WriteString("\n")
+	writeChart(&b, points, func(p SweepPoint) (string, string) {
+		return bar(p.Throughput, maxThroughput(points), "#"), formatThroughput(p.Throughput)
+	})
+	b.WriteString("\n")
+
+	b.WriteString(ansi.Bold("→ Latency") + " (# p50, = p90, - p99)")
+	b.WriteString("\n")
+	maxLatency := float64(maxP99(points))
+	writeChart(&b, points, func(p SweepPoint) (string, string) {
+		p50 := bar(float64(p.P50), maxLatency, "#")
+		p90 := bar(float64(p.P90), maxLatency, "=")
+		p99 := bar(float64(p.P99), maxLatency, "-")
+		// each percentile extends the bar of the lower one
+		return p50 + extension(p90, p50) + extension(p99, p90), formatMs(p.P99)
+	})
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// writeChart writes a bar chart of points, one line per point labeled
+// with its value, the bar and its legend being returned by line.
+func writeChart(b *strings.Builder, points []SweepPoint, line func(SweepPoint) (bars, legend string)) {
+	width := 0
+	for _, p := range points {
+		if n := utf8.RuneCountInString(p.Value); n > width {
+			width = n
+		}
+	}
+
+	for _, p := range points {
+		label := strings.Repeat(" ", width-utf8.RuneCountInString(p.Value)) + p.Value
+		if p.Knee {
+			label = ansi.Yellow(label)
+		}
+		if p.Failed {
+			fmt.Fprintf(b, "%s  %s\n", label, ansi.Red("ERROR"))
+			continue
+		}
+
+		bars, legend := line(p)
+		fmt.Fprintf(b, "%s  %-*s  %s", label, chartWidth, bars, legend)
+		if p.Knee {
+			b.WriteString(ansi.Yellow("  ◀ knee"))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// bar returns a bar of the given character, of a length proportional
+// to v relatively to max.
+func bar(v, highest float64, char string) string {
+	if highest <= 0 {
+		return ""
+	}
+	return strings.Repeat(char, int(math.Round(v/highest*chartWidth)))
+}
+
+func maxThroughput(points []SweepPoint) float64 {
+	highest := 0.0
+	for _, p := range points {
+		if !p.Failed && p.Throughput > highest {
+			highest = p.Throughput
+		}
+	}
+	return highest
+}
+
+func maxP99(points []SweepPoint) time.Duration {
+	var highest time.Duration
+	for _, p := range points {
+		if !p.Failed && p.P99 > highest {
+			highest = p.P99
+		}
+	}
+	return highest
+}
+
+func formatThroughput(v float64) string {
+	return fmt.Sprintf("%.1f/s", v)
+}
+
+// extension returns the part of full exceeding shorter.
+func extension(full, shorter string) string {
+	if len(full) <= len(shorter) {
+		return ""
+	}
+	return full[len(shorter):]
+}
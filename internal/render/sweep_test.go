@@ -0,0 +1,36 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func TestSweepString(t *testing.T) {
+	got := render.SweepString("concurrency", []render.SweepPoint{
+		{Value: "1", Requests: 100, Throughput: 50, P50: 10 * time.Millisecond, P90: 20 * time.Millisecond, P99: 20 * time.Millisecond},
+		{Value: "2", Requests: 100, Throughput: 100, P50: 10 * time.Millisecond, P90: 20 * time.Millisecond, P99: 40 * time.Millisecond, Knee: true},
+		{Value: "4", Failed: true},
+	})
+
+	for _, exp := range []string{
+		ansi.Bold("→ Sweep: concurrency") + "\n",
+		"1            100       0       50.0/s      10ms  20ms  20ms\n",
+		ansi.Yellow("2 (knee)") + "     100       0       100.0/s     10ms  20ms  40ms\n",
+		"4            -         -       -           -     -     " + ansi.Red("ERROR") + "\n",
+		// throughput bars
+		"1  " + strings.Repeat("#", 20) + strings.Repeat(" ", 20) + "  50.0/s\n",
+		ansi.Yellow("2") + "  " + strings.Repeat("#", 40) + "  100.0/s" + ansi.Yellow("  ◀ knee") + "\n",
+		"4  " + ansi.Red("ERROR") + "\n",
+		// latency bars: p50, then p90, then p99
+		"1  " + strings.Repeat("#", 10) + strings.Repeat("=", 10) + strings.Repeat(" ", 20) + "  20ms\n",
+		ansi.Yellow("2") + "  " + strings.Repeat("#", 10) + strings.Repeat("=", 10) + strings.Repeat("-", 20) + "  40ms",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("missing:\n%q\nin:\n%s", exp, got)
+		}
+	}
+}
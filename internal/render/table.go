@@ -0,0 +1,129 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+// Benchmark is a named report, as rendered among others
+// by BenchmarksTable.
+type Benchmark struct {
+	// Name identifies the benchmark, e.g. the path of its config file.
+	Name string
+
+	// Report is the report of the benchmark, nil if it could not run.
+	Report *runner.Report
+}
+
+// BenchmarksTable writes the table of BenchmarksTableString to w.
+func BenchmarksTable(w io.Writer, benchmarks []Benchmark) (int, error) {
+	return w.Write([]byte(BenchmarksTableString(benchmarks)))
+}
+
+// BenchmarksTableString returns a table comparing the main metrics
+// of several benchmarks, one row per benchmark.
+func BenchmarksTableString(benchmarks []Benchmark) string {
+	var b strings.Builder
+
+	b.WriteString(ansi.Bold("→ Benchmarks"))
+	b.WriteString("\n")
+
+	header := []string{"Benchmark", "Requests", "Errors", "Min", "Max", "Mean", "Duration", "Tests"}
+	rows := make([][]cell, 0, len(benchmarks))
+	for _, bench := range benchmarks {
+		rep := bench.Report
+		if rep == nil {
+			rows = append(rows, []cell{
+				{text: bench.Name}, {text: "-"}, {text: "-"}, {text: "-"},
+				{text: "-"}, {text: "-"}, {text: "-"}, {text: "ERROR", style: ansi.Red},
+			})
+			continue
+		}
+
+		m := rep.Metrics
+		rows = append(rows, []cell{
+			{text: bench.Name},
+			{text: strconv.Itoa(len(m.Records))},
+			{text: strconv.Itoa(len(m.RequestFailures))},
+			{text: formatMs(m.ResponseTimes.Min)},
+			{text: formatMs(m.ResponseTimes.Max)},
+			{text: formatMs(m.ResponseTimes.Mean)},
+			{text: formatMs(rep.Metadata.TotalDuration)},
+			testsCell(rep.Tests),
+		})
+	}
+
+	writeTable(&b, header, rows)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// testsCell returns the cell of the result of a test suite: PASS, FAIL,
+// or a dash if the suite is empty.
+func testsCell(suite runner.TestSuiteResults) cell {
+	switch {
+	case len(suite.Results) == 0:
+		return cell{text: "-"}
+	case suite.Pass:
+		return cell{text: "PASS", style: ansi.Green}
+	default:
+		return cell{text: "FAIL", style: ansi.Red}
+	}
+}
+
+// cell is a table cell: its text, and the style applied to it
+// once padded, if any.
+type cell struct {
+	text  string
+	style ansi.StyleFunc
+}
+
+// writeTable writes rows under header, the columns being aligned
+// on their widest cell.
+func writeTable(b *strings.Builder, header []string, rows [][]cell) {
+	widths := make([]int, len(header))
+	for i, title := range header {
+		widths[i] = utf8.RuneCountInString(title)
+	}
+	for _, row := range rows {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c.text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	headerCells := make([]cell, len(header))
+	for i, title := range header {
+		headerCells[i] = cell{text: title, style: ansi.Bold}
+	}
+
+	for _, row := range append([][]cell{headerCells}, rows...) {
+		for i, c := range row {
+			text := c.text
+			if i != len(row)-1 {
+				text += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(text)+2)
+			}
+			if c.style != nil {
+				// style the text only, not the padding
+				trimmed := strings.TrimRight(text, " ")
+				text = c.style(trimmed) + text[len(trimmed):]
+			}
+			b.WriteString(text)
+		}
+		b.WriteString("\n")
+	}
+}
+
+func formatMs(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
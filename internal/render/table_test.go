@@ -0,0 +1,48 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+)
+
+func TestBenchmarksTableString(t *testing.T) {
+	metrics, duration := metricsStub()
+	rep := func(tests runner.TestSuiteResults) *runner.Report {
+		return &runner.Report{
+			Metrics:  metrics,
+			Metadata: runner.ReportMetadata{Config: configStub(), TotalDuration: duration},
+			Tests:    tests,
+		}
+	}
+
+	got := render.BenchmarksTableString([]render.Benchmark{
+		{Name: "bench/users.yml", Report: rep(runner.TestSuiteResults{
+			Pass:    true,
+			Results: []runner.TestCaseResult{{Pass: true}},
+		})},
+		{Name: "bench/orders.yml", Report: rep(runner.TestSuiteResults{
+			Pass:    false,
+			Results: []runner.TestCaseResult{{Pass: false}},
+		})},
+		{Name: "bench/health.yml", Report: rep(runner.TestSuiteResults{})},
+		{Name: "bench/broken.yml"},
+	})
+
+	exp := ansi.Bold("→ Benchmarks") + "\n" +
+		ansi.Bold("Benchmark") + "         " + ansi.Bold("Requests") + "  " + ansi.Bold("Errors") + "  " +
+		ansi.Bold("Min") + "     " + ansi.Bold("Max") + "     " + ansi.Bold("Mean") + "    " +
+		ansi.Bold("Duration") + "  " + ansi.Bold("Tests") + "\n" +
+		"bench/users.yml   3         1       4000ms  6000ms  5000ms  15000ms   " + ansi.Green("PASS") + "\n" +
+		"bench/orders.yml  3         1       4000ms  6000ms  5000ms  15000ms   " + ansi.Red("FAIL") + "\n" +
+		"bench/health.yml  3         1       4000ms  6000ms  5000ms  15000ms   -\n" +
+		"bench/broken.yml  -         -       -       -       -       -         " + ansi.Red("ERROR") + "\n" +
+		"\n"
+
+	if got != exp {
+		t.Errorf("\nexp:\n%s\ngot:\n%s", exp, got)
+	}
+}
@@ -1,20 +1,23 @@
 package render
 
 import (
+	"fmt"
 	"io"
 	"strings"
 
 	"github.com/benchttp/engine/runner"
 
 	"github.com/benchttp/cli/internal/render/ansi"
+	"github.com/benchttp/cli/internal/testsuite"
 )
 
-func TestSuite(w io.Writer, suite runner.TestSuiteResults) (int, error) {
-	return w.Write([]byte(TestSuiteString(suite)))
+func TestSuite(w io.Writer, suite runner.TestSuiteResults, warnings testsuite.Warnings) (int, error) {
+	return w.Write([]byte(TestSuiteString(suite, warnings)))
 }
 
 // String returns a default summary of the Report as a string.
-func TestSuiteString(suite runner.TestSuiteResults) string {
+// The failing tests named in warnings are rendered as WARN.
+func TestSuiteString(suite runner.TestSuiteResults, warnings testsuite.Warnings) string {
 	if len(suite.Results) == 0 {
 		return ""
 	}
@@ -25,11 +28,19 @@ func TestSuiteString(suite runner.TestSuiteResults) string {
 	b.WriteString("\n")
 
 	writeResultString(&b, suite.Pass)
+	if _, _, warned := warnings.Count(suite); warned != 0 {
+		b.WriteString(" ")
+		b.WriteString(ansi.Yellow(fmt.Sprintf("(%d warned)", warned)))
+	}
 	b.WriteString("\n")
 
 	for _, tr := range suite.Results {
 		writeIndent(&b, 1)
-		writeResultString(&b, tr.Pass)
+		if !tr.Pass && warnings.Has(tr.Input.Name) {
+			b.WriteString(ansi.Yellow("WARN"))
+		} else {
+			writeResultString(&b, tr.Pass)
+		}
 		b.WriteString(" ")
 		b.WriteString(tr.Input.Name)
 
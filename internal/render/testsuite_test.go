@@ -0,0 +1,28 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/render"
+	"github.com/benchttp/cli/internal/render/ansi"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+func TestTestSuiteString(t *testing.T) {
+	suite := runner.TestSuiteResults{Pass: true, Results: []runner.TestCaseResult{
+		{Input: runner.TestCase{Name: "no failure"}, Pass: true},
+		{Input: runner.TestCase{Name: "aspirational mean"}, Pass: false, Summary: "want ResponseTimes.Mean <= 50ms, got 80ms"},
+	}}
+
+	exp := ansi.Bold("→ Test suite") + "\n" +
+		ansi.Green("PASS") + " " + ansi.Yellow("(1 warned)") + "\n" +
+		"  " + ansi.Green("PASS") + " no failure\n" +
+		"  " + ansi.Yellow("WARN") + " aspirational mean\n" +
+		"       " + ansi.Bold("→ ") + "want ResponseTimes.Mean <= 50ms, got 80ms\n"
+
+	if got := render.TestSuiteString(suite, testsuite.Warnings{"aspirational mean"}); got != exp {
+		t.Errorf("\nexp:\n%s\ngot:\n%s", exp, got)
+	}
+}
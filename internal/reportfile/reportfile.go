@@ -0,0 +1,133 @@
+// Package reportfile saves benchmark reports as JSON files and reads
+// them back, so that the results of different runs can be compared.
+//
+// A report read back renders like the original one. However, the
+// values of its test cases lose their types: durations and counts are
+// read as float64.
+package reportfile
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/errorutil"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+var (
+	// ErrFileNotFound signals a report file not found.
+	ErrFileNotFound = errors.New("report file not found")
+
+	// ErrFileRead signals an error trying to read a report file.
+	ErrFileRead = errors.New("invalid report file")
+
+	// ErrFileWrite signals an error trying to write a report file.
+	ErrFileWrite = errors.New("cannot write report file")
+)
+
+// version is the version of the file format, incremented on breaking
+// changes.
+const version = 1
+
+// file is the representation of a report file.
+type file struct {
+	Version int `json:"version"`
+
+	// URL is the url of the benchmarked request, that *url.URL does not
+	// represent faithfully in JSON.
+	URL string `json:"url"`
+
+	Report *runner.Report `json:"report"`
+
+	// Warnings are the names of the test cases of severity warn, whose
+	// failures did not fail the run.
+	Warnings testsuite.Warnings `json:"warnings,omitempty"`
+
+	// Tests counts the results of the test cases of the report,
+	// for the tools that do not interpret them.
+	Tests testCounts `json:"tests"`
+}
+
+// testCounts counts the results of a test suite by outcome. The failing
+// warnings are counted apart from the failing tests.
+type testCounts struct {
+	Pass     bool `json:"pass"`
+	Passed   int  `json:"passed"`
+	Failed   int  `json:"failed"`
+	Warnings int  `json:"warnings"`
+}
+
+// Marshal returns the JSON representation of rep, the test cases
+// named in warnings being of severity warn.
+func Marshal(rep *runner.Report, warnings testsuite.Warnings) ([]byte, error) {
+	f := file{Version: version, Report: withURL(rep, nil), Warnings: warnings}
+	f.Tests.Pass = rep.Tests.Pass
+	f.Tests.Passed, f.Tests.Failed, f.Tests.Warnings = warnings.Count(rep.Tests)
+	if u := rep.Metadata.Config.Request.URL; u != nil {
+		f.URL = u.String()
+	}
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Unmarshal parses the JSON representation of a report, and returns it
+// with the names of its test cases of severity warn.
+func Unmarshal(b []byte) (*runner.Report, testsuite.Warnings, error) {
+	var f file
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, nil, err
+	}
+	if f.Report == nil {
+		return nil, nil, errors.New("missing report")
+	}
+	if f.Version != version {
+		return nil, nil, errors.New("unsupported version")
+	}
+
+	u, err := url.Parse(f.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return withURL(f.Report, u), f.Warnings, nil
+}
+
+// Write writes rep as JSON to filename, the test cases named
+// in warnings being of severity warn.
+func Write(filename string, rep *runner.Report, warnings testsuite.Warnings) error {
+	b, err := Marshal(rep, warnings)
+	if err != nil {
+		return errorutil.WithDetails(ErrFileWrite, filename, err)
+	}
+	if err := os.WriteFile(filename, append(b, '\n'), 0o600); err != nil {
+		return errorutil.WithDetails(ErrFileWrite, filename, err)
+	}
+	return nil
+}
+
+// Read reads the report written to filename by Write, and the names
+// of its test cases of severity warn.
+func Read(filename string) (*runner.Report, testsuite.Warnings, error) {
+	b, err := os.ReadFile(filename)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil, errorutil.WithDetails(ErrFileNotFound, filename)
+	case err != nil:
+		return nil, nil, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+
+	rep, warnings, err := Unmarshal(b)
+	if err != nil {
+		return nil, nil, errorutil.WithDetails(ErrFileRead, filename, err)
+	}
+	return rep, warnings, nil
+}
+
+// withURL returns a copy of rep with the url of its request set to u.
+func withURL(rep *runner.Report, u *url.URL) *runner.Report {
+	copied := *rep
+	copied.Metadata.Config.Request.URL = u
+	return &copied
+}
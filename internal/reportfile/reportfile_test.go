@@ -0,0 +1,99 @@
+package reportfile_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/reportfile"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+func TestWriteRead(t *testing.T) {
+	rep := &runner.Report{}
+	rep.Metadata.Config.Request = rep.Metadata.Config.Request.WithURL("https://example.com/users?page=2")
+	rep.Metadata.Config.Runner.Requests = 3
+	rep.Metadata.TotalDuration = 3 * time.Second
+	rep.Metrics.ResponseTimes.Mean = 2 * time.Millisecond
+	rep.Metrics.Records = []struct{ ResponseTime time.Duration }{{1}, {2}, {3}}
+	rep.Metrics.RequestFailures = []struct{ Reason string }{{"timeout"}}
+	rep.Metrics.StatusCodesDistribution = map[int]int{200: 2}
+	rep.Tests = runner.TestSuiteResults{Results: []runner.TestCaseResult{
+		{Input: runner.TestCase{Name: "mean"}, Summary: "want ResponseTimes.Mean < 1ms, got 2ms"},
+	}}
+
+	filename := filepath.Join(t.TempDir(), "report.json")
+	if err := reportfile.Write(filename, rep, testsuite.Warnings{"mean"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, warnings, err := reportfile.Read(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u := got.Metadata.Config.Request.URL.String(); u != "https://example.com/users?page=2" {
+		t.Errorf("exp url to be restored, got %s", u)
+	}
+	if !reflect.DeepEqual(got.Metrics, rep.Metrics) {
+		t.Errorf("metrics:\nexp %+v\ngot %+v", rep.Metrics, got.Metrics)
+	}
+	if got.Metadata.TotalDuration != rep.Metadata.TotalDuration || got.Metadata.Config.Runner != rep.Metadata.Config.Runner {
+		t.Errorf("metadata:\nexp %+v\ngot %+v", rep.Metadata, got.Metadata)
+	}
+	if r := got.Tests.Results; len(r) != 1 || r[0].Input.Name != "mean" || r[0].Summary != rep.Tests.Results[0].Summary {
+		t.Errorf("tests: exp %+v, got %+v", rep.Tests, got.Tests)
+	}
+	if !reflect.DeepEqual(warnings, testsuite.Warnings{"mean"}) {
+		t.Errorf("warnings: exp [mean], got %v", warnings)
+	}
+	if rep.Metadata.Config.Request.URL == nil {
+		t.Error("the written report was modified")
+	}
+
+	t.Run("count failing warnings apart", func(t *testing.T) {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var f struct {
+			Tests struct {
+				Passed, Failed, Warnings int
+			}
+		}
+		if err := json.Unmarshal(b, &f); err != nil {
+			t.Fatal(err)
+		}
+		if f.Tests.Passed != 0 || f.Tests.Failed != 0 || f.Tests.Warnings != 1 {
+			t.Errorf("exp 1 warning, got %+v", f.Tests)
+		}
+	})
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, _, err := reportfile.Read(filepath.Join(dir, "missing.json")); !errors.Is(err, reportfile.ErrFileNotFound) {
+		t.Errorf("exp ErrFileNotFound, got %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"invalid.json": "{",
+		"empty.json":   "{}",
+		"version.json": `{"version":99,"report":{}}`,
+	} {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := reportfile.Read(filename); !errors.Is(err, reportfile.ErrFileRead) {
+			t.Errorf("%s: exp ErrFileRead, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// MannWhitney runs the Mann–Whitney U test on samples a and b, using
+// the normal approximation with tie and continuity corrections.
+// It returns the z-score of b relatively to a, positive if b tends to be
+// greater than a, and the two-sided p-value of the test.
+func MannWhitney(a, b []time.Duration) (z, p float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	type sample struct {
+		v   time.Duration
+		inB bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v: v})
+	}
+	for _, v := range b {
+		all = append(all, sample{v: v, inB: true})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// sum the ranks of b, tied values sharing their mean rank
+	rankSumB, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // mean of ranks i+1..j
+		for k := i; k < j; k++ {
+			if all[k].inB {
+				rankSumB += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSumB - n2*(n2+1)/2
+	mean := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 0, 1
+	}
+
+	diff := u - mean
+	switch {
+	case diff > 0.5:
+		diff -= 0.5
+	case diff < -0.5:
+		diff += 0.5
+	default:
+		diff = 0
+	}
+	z = diff / sigma
+	return z, 2 * (1 - NormalCDF(math.Abs(z)))
+}
+
+// BootstrapPercentile estimates the confidence interval of the difference
+// between the given percentile of b and of a, by resampling them with
+// replacement the given number of times. The resampling is seeded, so the
+// results are reproducible.
+func BootstrapPercentile(a, b []time.Duration, percentile, confidence float64, iterations int, seed int64) (low, high time.Duration) {
+	if len(a) == 0 || len(b) == 0 || iterations < 1 {
+		return 0, 0
+	}
+
+	sortedA, sortedB := sorted(a), sorted(b)
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // reproducibility matters, not unpredictability
+
+	diffs := make([]time.Duration, iterations)
+	size := len(a)
+	if len(b) > size {
+		size = len(b)
+	}
+	counts := make([]int, size)
+	for i := range diffs {
+		diffs[i] = resampledPercentile(sortedB, percentile, rng, counts) -
+			resampledPercentile(sortedA, percentile, rng, counts)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+
+	return Percentile(diffs, (1-confidence)/2*100), Percentile(diffs, (1+confidence)/2*100)
+}
+
+// resampledPercentile returns the percentile of a resample with
+// replacement of sorted, using counts as a buffer of len(sorted)
+// at least. Rather than sorting the resample, it counts how many times
+// each value is drawn, sorted being already ordered.
+func resampledPercentile(sorted []time.Duration, percentile float64, rng *rand.Rand, counts []int) time.Duration {
+	n := len(sorted)
+	counts = counts[:n]
+	for i := range counts {
+		counts[i] = 0
+	}
+	for i := 0; i < n; i++ {
+		counts[rng.Intn(n)]++
+	}
+
+	rank := int(math.Ceil(percentile / 100 * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	for i, count := range counts {
+		rank -= count
+		if rank <= 0 {
+			return sorted[i]
+		}
+	}
+	return sorted[n-1]
+}
+
+func sorted(values []time.Duration) []time.Duration {
+	s := make([]time.Duration, len(values))
+	copy(s, values)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
@@ -0,0 +1,70 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+// shifted returns n durations spread over [from, from+n*step[.
+func shifted(n int, from, step time.Duration) []time.Duration {
+	values := make([]time.Duration, n)
+	for i := range values {
+		values[i] = from + time.Duration(i)*step
+	}
+	return values
+}
+
+func TestMannWhitney(t *testing.T) {
+	t.Run("reference values", func(t *testing.T) {
+		// U = 5 for b over a: z = (5 - 12.5 + 0.5) / sqrt(25*11/12)
+		a := []time.Duration{6, 7, 8, 9, 10}
+		b := []time.Duration{1, 2, 3, 4, 11}
+		z, p := stats.MannWhitney(a, b)
+		if math.Abs(z-(-1.4623)) > 1e-3 || math.Abs(p-0.1437) > 1e-3 {
+			t.Errorf("exp z=-1.4623 p=0.1437, got z=%.4f p=%.4f", z, p)
+		}
+	})
+
+	t.Run("slower", func(t *testing.T) {
+		z, p := stats.MannWhitney(shifted(100, 10, 1), shifted(100, 40, 1))
+		if z <= 0 || p > 0.001 {
+			t.Errorf("exp significant positive z, got z=%v p=%v", z, p)
+		}
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		z, p := stats.MannWhitney(shifted(100, 10, 1), shifted(100, 10, 1))
+		if z != 0 || p != 1 {
+			t.Errorf("exp z=0 p=1, got z=%v p=%v", z, p)
+		}
+	})
+
+	t.Run("all ties", func(t *testing.T) {
+		if _, p := stats.MannWhitney(shifted(10, 5, 0), shifted(10, 5, 0)); p != 1 {
+			t.Errorf("exp p=1, got %v", p)
+		}
+	})
+}
+
+func TestBootstrapPercentile(t *testing.T) {
+	a := shifted(1000, 10*time.Millisecond, 10*time.Microsecond)
+	b := shifted(1000, 15*time.Millisecond, 10*time.Microsecond)
+
+	low, high := stats.BootstrapPercentile(a, b, 50, 0.95, 1000, 1)
+	if low > 5*time.Millisecond || high < 5*time.Millisecond || low <= 0 {
+		t.Errorf("exp an interval around 5ms excluding 0, got [%v, %v]", low, high)
+	}
+
+	low2, high2 := stats.BootstrapPercentile(a, b, 50, 0.95, 1000, 1)
+	if low2 != low || high2 != high {
+		t.Error("exp reproducible results for a same seed")
+	}
+
+	low, high = stats.BootstrapPercentile(a, a, 99, 0.95, 1000, 1)
+	if low > 0 || high < 0 {
+		t.Errorf("exp an interval including 0 for a same sample, got [%v, %v]", low, high)
+	}
+}
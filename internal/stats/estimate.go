@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"math"
+)
+
+// Estimate is the estimate of the mean of a population from a sample,
+// with the confidence interval [Mean-Margin, Mean+Margin].
+type Estimate struct {
+	N      int
+	Mean   float64
+	StdDev float64
+	Margin float64
+}
+
+// Low returns the lower bound of the confidence interval.
+func (e Estimate) Low() float64 {
+	return e.Mean - e.Margin
+}
+
+// High returns the upper bound of the confidence interval.
+func (e Estimate) High() float64 {
+	return e.Mean + e.Margin
+}
+
+// NewEstimate returns the estimate of the mean of the population
+// of values at the given confidence level (e.g. 0.95), using Student's
+// t-distribution. Its margin is infinite for less than 2 values.
+func NewEstimate(values []float64, confidence float64) Estimate {
+	n := len(values)
+	if n == 0 {
+		return Estimate{}
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(n)
+	if n == 1 {
+		return Estimate{N: 1, Mean: mean, Margin: math.Inf(1)}
+	}
+
+	squares := 0.0
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(squares / float64(n-1))
+	t := StudentQuantile((1+confidence)/2, float64(n-1))
+
+	return Estimate{
+		N:      n,
+		Mean:   mean,
+		StdDev: stddev,
+		Margin: t * stddev / math.Sqrt(float64(n)),
+	}
+}
+
+// StudentQuantile returns the p-quantile of Student's t-distribution
+// with df degrees of freedom.
+func StudentQuantile(p, df float64) float64 {
+	switch {
+	case p == 0.5:
+		return 0
+	case p < 0.5:
+		return -StudentQuantile(1-p, df)
+	}
+
+	// bisect over [0, hi], hi being doubled until it bounds the quantile
+	lo, hi := 0.0, 1.0
+	for studentCDF(hi, df) < p && hi < math.MaxFloat64/2 {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// NormalQuantile returns the p-quantile of the standard normal
+// distribution.
+func NormalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// NormalCDF returns the probability that a standard normal variable
+// is lower than or equal to z.
+func NormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// studentCDF returns the probability that a variable of Student's
+// t-distribution with df degrees of freedom is lower than or equal to t.
+func studentCDF(t, df float64) float64 {
+	tail := 0.5 * regularizedBeta(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// regularizedBeta returns the regularized incomplete beta function
+// I_x(a, b), evaluated by its continued fraction.
+func regularizedBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// the continued fraction converges quickly for x < (a+1)/(a+b+2),
+	// else use the symmetry I_x(a, b) = 1 - I_(1-x)(b, a)
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(a, b, x) / a
+	}
+	return 1 - front*betaFraction(b, a, 1-x)/b
+}
+
+// betaFraction evaluates the continued fraction of the incomplete beta
+// function with the modified Lentz's method.
+func betaFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+
+	nonZero := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+
+	c, d := 1.0, 1/nonZero(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1.0; m <= maxIterations; m++ {
+		// even step
+		num := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 / nonZero(1+num*d)
+		c = nonZero(1 + num/c)
+		h *= d * c
+
+		// odd step
+		num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 / nonZero(1+num*d)
+		c = nonZero(1 + num/c)
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,88 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+func TestStudentQuantile(t *testing.T) {
+	testcases := []struct {
+		p, df, exp float64
+	}{
+		{p: 0.975, df: 1, exp: 12.706},
+		{p: 0.975, df: 4, exp: 2.776},
+		{p: 0.995, df: 9, exp: 3.250},
+		{p: 0.95, df: 30, exp: 1.697},
+		{p: 0.025, df: 4, exp: -2.776},
+		{p: 0.5, df: 4, exp: 0},
+	}
+
+	for _, tc := range testcases {
+		if got := stats.StudentQuantile(tc.p, tc.df); math.Abs(got-tc.exp) > 1e-3 {
+			t.Errorf("p=%v df=%v: exp %v, got %v", tc.p, tc.df, tc.exp, got)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	if got := stats.NormalQuantile(0.975); math.Abs(got-1.95996) > 1e-4 {
+		t.Errorf("exp 1.96, got %v", got)
+	}
+	if got := stats.NormalCDF(1.95996); math.Abs(got-0.975) > 1e-4 {
+		t.Errorf("exp 0.975, got %v", got)
+	}
+}
+
+func TestNewEstimate(t *testing.T) {
+	got := stats.NewEstimate([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 0.95)
+
+	if got.N != 8 || got.Mean != 5 {
+		t.Errorf("exp n=8 mean=5, got n=%d mean=%v", got.N, got.Mean)
+	}
+	if math.Abs(got.StdDev-2.138) > 1e-3 {
+		t.Errorf("exp stddev 2.138, got %v", got.StdDev)
+	}
+	// t(0.975, 7) = 2.365
+	if math.Abs(got.Margin-2.365*2.138/math.Sqrt(8)) > 1e-2 {
+		t.Errorf("exp margin 1.787, got %v", got.Margin)
+	}
+	if got.Low() != got.Mean-got.Margin || got.High() != got.Mean+got.Margin {
+		t.Errorf("bad bounds: %v, %v", got.Low(), got.High())
+	}
+
+	if single := stats.NewEstimate([]float64{3}, 0.95); !math.IsInf(single.Margin, 1) {
+		t.Errorf("exp infinite margin for a single value, got %v", single.Margin)
+	}
+}
+
+func TestRuns_Mean(t *testing.T) {
+	rep := func(mean time.Duration, failures int) *runner.Report {
+		r := &runner.Report{}
+		r.Metrics.ResponseTimes.Mean = mean
+		r.Metrics.RequestFailures = make([]struct{ Reason string }, failures)
+		r.Metrics.Records = []struct{ ResponseTime time.Duration }{{mean}}
+		return r
+	}
+	runs := stats.Runs{rep(10*time.Millisecond, 1), rep(20*time.Millisecond, 2)}
+
+	if got := runs.Mean("ResponseTimes.Mean"); got != 15*time.Millisecond {
+		t.Errorf("exp 15ms, got %v", got)
+	}
+	if got := runs.Mean("RequestFailureCount"); got != 2 {
+		t.Errorf("exp 2 (rounded 1.5), got %v", got)
+	}
+	if got := runs.Mean("ResponseTimes.P99"); got != 15*time.Millisecond {
+		t.Errorf("exp 15ms for a percentile, got %v", got)
+	}
+	if got := runs.Mean("StatusCodesDistribution.404"); got != 0 {
+		t.Errorf("exp 0 for a status code never received, got %v", got)
+	}
+	if got := runs.Mean("StatusCodesDistribution"); got != nil {
+		t.Errorf("exp nil for a non numeric metric, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// ErrField signals a metric field that is neither an int nor
+// a duration metric.
+var ErrField = errors.New("invalid metric field")
+
+// percentileField matches the fields of the percentiles of the response
+// times, such as ResponseTimes.P99 or ResponseTimes.P99.9, that are
+// computed from the records rather than by the engine.
+var percentileField = regexp.MustCompile(`(?i)^ResponseTimes\.P([0-9]+(\.[0-9]+)?)$`)
+
+// FieldType returns the type of the metric of field, "int" or
+// "time.Duration", or an ErrField if it is neither. Besides the fields
+// of the engine, it accepts the percentiles of the response times,
+// e.g. ResponseTimes.P99.
+func FieldType(field runner.MetricsField) (string, error) {
+	if _, ok := percentile(field); ok {
+		return "time.Duration", nil
+	}
+	if err := field.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrField, field)
+	}
+	switch typ := field.Type(); typ {
+	case "int", "time.Duration":
+		return typ, nil
+	default:
+		return "", fmt.Errorf("%w: %s: unsupported type %s", ErrField, field, typ)
+	}
+}
+
+// Metric returns the value of the metric of field in rep, an int or
+// a duration, as accepted by FieldType. A missing value, such as the
+// count of a status code never received, is the zero value of the type
+// of the metric.
+func Metric(rep *runner.Report, field runner.MetricsField) (v runner.MetricsValue) {
+	if p, ok := percentile(field); ok {
+		return Percentile(ResponseTimes(rep), p)
+	}
+
+	zero := runner.MetricsValue(0)
+	if field.Type() == "time.Duration" {
+		zero = time.Duration(0)
+	}
+
+	// the engine panics resolving a key missing from a map
+	defer func() {
+		if recover() != nil {
+			v = zero
+		}
+	}()
+
+	if v := rep.Metrics.MetricOf(field).Value; v != nil {
+		return v
+	}
+	return zero
+}
+
+// percentile returns the percentile of a percentile field,
+// and whether it is one.
+func percentile(field runner.MetricsField) (float64, bool) {
+	m := percentileField.FindStringSubmatch(string(field))
+	if m == nil {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(m[1], 64)
+	if err != nil || p <= 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
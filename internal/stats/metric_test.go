@@ -0,0 +1,56 @@
+package stats_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+func TestFieldType(t *testing.T) {
+	for field, exp := range map[runner.MetricsField]string{
+		"ResponseTimes.Mean":          "time.Duration",
+		"ResponseTimes.P99":           "time.Duration",
+		"responsetimes.p99.9":         "time.Duration",
+		"RequestFailureCount":         "int",
+		"StatusCodesDistribution.200": "int",
+	} {
+		got, err := stats.FieldType(field)
+		if err != nil {
+			t.Errorf("%s: %v", field, err)
+		}
+		if got != exp {
+			t.Errorf("%s: exp %s, got %s", field, exp, got)
+		}
+	}
+
+	for _, field := range []runner.MetricsField{"ResponseTimes.P0", "ResponseTimes.P101", "ResponseTimes.Nope", "ResponseTimes.Deciles"} {
+		if _, err := stats.FieldType(field); !errors.Is(err, stats.ErrField) {
+			t.Errorf("%s: exp ErrField, got %v", field, err)
+		}
+	}
+}
+
+func TestMetric(t *testing.T) {
+	rep := &runner.Report{}
+	for i := 1; i <= 100; i++ {
+		rep.Metrics.Records = append(rep.Metrics.Records, struct{ ResponseTime time.Duration }{time.Duration(i) * time.Millisecond})
+	}
+	rep.Metrics.ResponseTimes.Mean = 50 * time.Millisecond
+	rep.Metrics.StatusCodesDistribution = map[int]int{200: 100}
+
+	for field, exp := range map[runner.MetricsField]runner.MetricsValue{
+		"ResponseTimes.Mean":          50 * time.Millisecond,
+		"ResponseTimes.P99":           99 * time.Millisecond,
+		"RequestCount":                100,
+		"StatusCodesDistribution.200": 100,
+		"StatusCodesDistribution.500": 0,
+	} {
+		if got := stats.Metric(rep, field); got != exp {
+			t.Errorf("%s: exp %v (%T), got %v (%T)", field, exp, exp, got, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// Runs are the reports of repeated runs of a same benchmark.
+type Runs []*runner.Report
+
+// Estimate returns the estimate of the metric returned by value over
+// the runs, at the given confidence level.
+func (runs Runs) Estimate(value func(*runner.Report) float64, confidence float64) Estimate {
+	values := make([]float64, len(runs))
+	for i, rep := range runs {
+		values[i] = value(rep)
+	}
+	return NewEstimate(values, confidence)
+}
+
+// Mean returns the mean over the runs of the metric of the given field,
+// of the same type as the metric. It accepts the fields accepted by
+// FieldType, percentiles of the response times included, and returns
+// nil for the other ones.
+func (runs Runs) Mean(field runner.MetricsField) runner.MetricsValue {
+	typ, err := FieldType(field)
+	if len(runs) == 0 || err != nil {
+		return nil
+	}
+
+	sum := 0.0
+	for _, rep := range runs {
+		v, _ := Float(Metric(rep, field))
+		sum += v
+	}
+	mean := math.Round(sum / float64(len(runs)))
+
+	if typ == "time.Duration" {
+		return time.Duration(mean)
+	}
+	return int(mean)
+}
+
+// Float returns v as a float64 if it is an int or a duration,
+// and whether it is.
+func Float(v runner.MetricsValue) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case time.Duration:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+// Package stats computes statistics over benchmark reports that the
+// engine does not provide, such as arbitrary percentiles.
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// ResponseTimes returns the response times of the records of rep,
+// sorted from the fastest.
+func ResponseTimes(rep *runner.Report) []time.Duration {
+	times := make([]time.Duration, len(rep.Metrics.Records))
+	for i, record := range rep.Metrics.Records {
+		times[i] = record.ResponseTime
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times
+}
+
+// Percentile returns the p-th percentile (0 < p <= 100) of sorted
+// using the nearest-rank method, or 0 if sorted is empty.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// Throughput returns the number of successful requests per second
+// of rep, or 0 if its duration is zero.
+func Throughput(rep *runner.Report) float64 {
+	if rep.Metadata.TotalDuration <= 0 {
+		return 0
+	}
+	succeeded := len(rep.Metrics.Records) - len(rep.Metrics.RequestFailures)
+	return float64(succeeded) / rep.Metadata.TotalDuration.Seconds()
+}
@@ -0,0 +1,66 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	testcases := []struct {
+		p   float64
+		exp time.Duration
+	}{
+		{p: 0, exp: 1 * time.Millisecond},
+		{p: 50, exp: 50 * time.Millisecond},
+		{p: 99, exp: 99 * time.Millisecond},
+		{p: 99.9, exp: 100 * time.Millisecond},
+		{p: 100, exp: 100 * time.Millisecond},
+	}
+
+	for _, tc := range testcases {
+		if got := stats.Percentile(sorted, tc.p); got != tc.exp {
+			t.Errorf("p%v: exp %v, got %v", tc.p, tc.exp, got)
+		}
+	}
+
+	if got := stats.Percentile(nil, 50); got != 0 {
+		t.Errorf("empty: exp 0, got %v", got)
+	}
+}
+
+func TestResponseTimes(t *testing.T) {
+	rep := &runner.Report{}
+	for _, d := range []time.Duration{3, 1, 2} {
+		rep.Metrics.Records = append(rep.Metrics.Records, struct{ ResponseTime time.Duration }{d})
+	}
+
+	got := stats.ResponseTimes(rep)
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("exp sorted [1 2 3], got %v", got)
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	rep := &runner.Report{}
+	rep.Metrics.Records = make([]struct{ ResponseTime time.Duration }, 10)
+	rep.Metrics.RequestFailures = make([]struct{ Reason string }, 2)
+	rep.Metadata.TotalDuration = 2 * time.Second
+
+	if got := stats.Throughput(rep); got != 4 {
+		t.Errorf("exp 4 req/s, got %v", got)
+	}
+
+	rep.Metadata.TotalDuration = 0
+	if got := stats.Throughput(rep); got != 0 {
+		t.Errorf("exp 0 req/s for a zero duration, got %v", got)
+	}
+}
@@ -0,0 +1,216 @@
+// Package sweep varies a runner parameter over a list of values, to find
+// the point where the benchmarked service saturates.
+package sweep
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/errorutil"
+)
+
+var (
+	// ErrParam signals an unknown or unsupported parameter.
+	ErrParam = errors.New("unsupported sweep parameter")
+
+	// ErrValues signals an invalid list or range of values.
+	ErrValues = errors.New("invalid sweep values")
+)
+
+// maxValues is the maximum number of values of a range, preventing
+// accidentally endless sweeps.
+const maxValues = 100
+
+// Param is a runner parameter that can be swept.
+type Param struct {
+	// Name is the name of the parameter, as a config field.
+	Name string
+
+	// duration is true if the values are durations, integers otherwise.
+	duration bool
+
+	// min is the minimum value of the parameter.
+	min int64
+
+	set func(cfg *runner.Config, v int64)
+}
+
+// Params lists the runner parameters that can be swept.
+var Params = []Param{
+	{
+		Name: runner.ConfigFieldConcurrency,
+		min:  1,
+		set:  func(cfg *runner.Config, v int64) { cfg.Runner.Concurrency = int(v) },
+	},
+	{
+		Name: runner.ConfigFieldRequests,
+		min:  -1, // unlimited
+		set:  func(cfg *runner.Config, v int64) { cfg.Runner.Requests = int(v) },
+	},
+	{
+		Name:     runner.ConfigFieldInterval,
+		duration: true,
+		set:      func(cfg *runner.Config, v int64) { cfg.Runner.Interval = time.Duration(v) },
+	},
+	{
+		Name:     runner.ConfigFieldRequestTimeout,
+		duration: true,
+		min:      1,
+		set:      func(cfg *runner.Config, v int64) { cfg.Runner.RequestTimeout = time.Duration(v) },
+	},
+}
+
+// ParamNames returns the names of Params.
+func ParamNames() []string {
+	names := make([]string, len(Params))
+	for i, p := range Params {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// LookupParam returns the Param of the given name.
+func LookupParam(name string) (Param, error) {
+	for _, p := range Params {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Param{}, errorutil.WithDetails(ErrParam, name, "expected one of "+strings.Join(ParamNames(), ", "))
+}
+
+// Set returns a copy of cfg with the parameter set to v.
+func (p Param) Set(cfg runner.Config, v int64) runner.Config {
+	p.set(&cfg, v)
+	return cfg
+}
+
+// Format returns the string representation of value v of the parameter.
+func (p Param) Format(v int64) string {
+	if p.duration {
+		return time.Duration(v).String()
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// ParseValues parses the values of the parameter from s, either a
+// comma-separated list ("1,2,4,8") or a range "<start>..<end>" with an
+// optional step, added ("10ms..100ms+10ms") or multiplied ("1..64*2").
+// The default step is +1 for integers and +1s for durations.
+// Values lower than the minimum of the parameter, such as a concurrency
+// of 0 or a negative interval, are an error.
+func (p Param) ParseValues(s string) ([]int64, error) {
+	values, err := p.parseValues(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		if v < p.min {
+			return nil, errorutil.WithDetails(ErrValues, s, p.Format(v)+" is lower than "+p.Format(p.min))
+		}
+	}
+	return values, nil
+}
+
+// parseValues parses the values of the parameter from s, see ParseValues,
+// without checking their minimum.
+func (p Param) parseValues(s string) ([]int64, error) {
+	if !strings.Contains(s, "..") {
+		fields := strings.Split(s, ",")
+		values := make([]int64, len(fields))
+		for i, field := range fields {
+			v, err := p.parseValue(field)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+
+	bounds := strings.SplitN(s, "..", 2)
+	start := bounds[0]
+	end, step, multiply := splitStep(bounds[1])
+	if step == "" {
+		step = "1"
+		if p.duration {
+			step = "1s"
+		}
+	}
+
+	from, err := p.parseValue(start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := p.parseValue(end)
+	if err != nil {
+		return nil, err
+	}
+	var by int64
+	if multiply {
+		by, err = strconv.ParseInt(step, 10, 64)
+	} else {
+		by, err = p.parseValue(step)
+	}
+	if err != nil {
+		return nil, errorutil.WithDetails(ErrValues, s, err)
+	}
+
+	return rangeValues(s, from, to, by, multiply)
+}
+
+// splitStep splits the end of a range from its step, returning whether
+// the step is a multiplier.
+func splitStep(end string) (value, step string, multiply bool) {
+	if i := strings.IndexAny(end, "*+"); i != -1 {
+		return end[:i], end[i+1:], end[i] == '*'
+	}
+	return end, "", false
+}
+
+// rangeValues returns the values from start to end included, by adding
+// or multiplying by step.
+func rangeValues(s string, start, end, step int64, multiply bool) ([]int64, error) {
+	switch {
+	case end < start:
+		return nil, errorutil.WithDetails(ErrValues, s, "end is lower than start")
+	case multiply && (step < 2 || start < 1):
+		return nil, errorutil.WithDetails(ErrValues, s, "a multiplied range needs a start >= 1 and a step >= 2")
+	case !multiply && step < 1:
+		return nil, errorutil.WithDetails(ErrValues, s, "step must be positive")
+	}
+
+	values := []int64{}
+	for v := start; v <= end; {
+		if len(values) == maxValues {
+			return nil, errorutil.WithDetails(ErrValues, s, "more than "+strconv.Itoa(maxValues)+" values")
+		}
+		values = append(values, v)
+		if multiply {
+			v *= step
+		} else {
+			v += step
+		}
+	}
+	return values, nil
+}
+
+func (p Param) parseValue(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if p.duration {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, errorutil.WithDetails(ErrValues, s, "expected a duration")
+		}
+		return int64(d), nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errorutil.WithDetails(ErrValues, s, "expected an integer")
+	}
+	return v, nil
+}
@@ -0,0 +1,75 @@
+package sweep
+
+import (
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+// kneeTolerance is the factor by which the growth of the latency must
+// exceed the growth of the throughput to be considered a knee, so that
+// measurement noise on a flat curve is not reported as one.
+const kneeTolerance = 1.1
+
+// kneePercentile is the latency percentile compared to the throughput
+// to find the knee.
+const kneePercentile = 90
+
+// Point is the result of the benchmark of one value of a sweep.
+type Point struct {
+	// Value is the value of the swept parameter.
+	Value int64
+
+	// Report is the report of the benchmark, nil if it failed.
+	Report *runner.Report
+}
+
+// Throughput returns the number of successful requests per second.
+func (p Point) Throughput() float64 {
+	if p.Report == nil {
+		return 0
+	}
+	return stats.Throughput(p.Report)
+}
+
+// Latency returns the given percentile of the response times.
+func (p Point) Latency(percentile float64) time.Duration {
+	if p.Report == nil {
+		return 0
+	}
+	return stats.Percentile(stats.ResponseTimes(p.Report), percentile)
+}
+
+// Knee returns the index of the knee of the sweep: the last point before
+// the latency climbs faster than the throughput, i.e. before the service
+// saturates. It returns -1 if there is no such point. The points are
+// expected to be ordered by increasing value; failed ones are skipped.
+func Knee(points []Point) int {
+	prev := -1
+	for i, point := range points {
+		if point.Report == nil {
+			continue
+		}
+		if prev != -1 && climbsFaster(points[prev], point) {
+			return prev
+		}
+		prev = i
+	}
+	return -1
+}
+
+// climbsFaster returns true if the latency grows faster than
+// the throughput from a to b.
+func climbsFaster(a, b Point) bool {
+	latencyA, latencyB := a.Latency(kneePercentile), b.Latency(kneePercentile)
+	throughputA, throughputB := a.Throughput(), b.Throughput()
+	if latencyA <= 0 || throughputA <= 0 {
+		return false
+	}
+
+	latencyGrowth := float64(latencyB) / float64(latencyA)
+	throughputGrowth := throughputB / throughputA
+	return latencyGrowth > throughputGrowth*kneeTolerance
+}
@@ -0,0 +1,143 @@
+package sweep_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/sweep"
+)
+
+func TestParam_ParseValues(t *testing.T) {
+	concurrency, _ := sweep.LookupParam("concurrency")
+	interval, _ := sweep.LookupParam("interval")
+	ms := int64(time.Millisecond)
+
+	testcases := []struct {
+		name  string
+		param sweep.Param
+		in    string
+		exp   []int64
+	}{
+		{name: "list", param: concurrency, in: "1, 2,4,8", exp: []int64{1, 2, 4, 8}},
+		{name: "range", param: concurrency, in: "1..4", exp: []int64{1, 2, 3, 4}},
+		{name: "added step", param: concurrency, in: "10..35+10", exp: []int64{10, 20, 30}},
+		{name: "multiplied step", param: concurrency, in: "1..64*2", exp: []int64{1, 2, 4, 8, 16, 32, 64}},
+		{name: "duration list", param: interval, in: "0s,10ms", exp: []int64{0, 10 * ms}},
+		{name: "duration range", param: interval, in: "10ms..30ms+10ms", exp: []int64{10 * ms, 20 * ms, 30 * ms}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.param.ParseValues(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("exp %v, got %v", tc.exp, got)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "a", "1,b", "4..1", "1..8*1", "0..8*2", "1..8+0", "1..1000", "1ms"} {
+		if _, err := concurrency.ParseValues(in); !errors.Is(err, sweep.ErrValues) {
+			t.Errorf("%q: exp ErrValues, got %v", in, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		param sweep.Param
+		in    string
+	}{
+		{param: concurrency, in: "0,1"},
+		{param: concurrency, in: "-2..2"},
+		{param: interval, in: "-10ms,0s"},
+	} {
+		if _, err := tc.param.ParseValues(tc.in); !errors.Is(err, sweep.ErrValues) {
+			t.Errorf("%s %q: exp ErrValues, got %v", tc.param.Name, tc.in, err)
+		}
+	}
+}
+
+func TestLookupParam(t *testing.T) {
+	p, err := sweep.LookupParam("requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := p.Set(runner.Config{}, 42); cfg.Runner.Requests != 42 {
+		t.Errorf("exp requests set to 42, got %d", cfg.Runner.Requests)
+	}
+
+	if _, err := sweep.LookupParam("url"); !errors.Is(err, sweep.ErrParam) {
+		t.Errorf("exp ErrParam, got %v", err)
+	}
+}
+
+func TestKnee(t *testing.T) {
+	// point returns a point of 100 requests of the given latency
+	// over the given duration.
+	point := func(v int64, latency, duration time.Duration) sweep.Point {
+		rep := &runner.Report{}
+		for i := 0; i < 100; i++ {
+			rep.Metrics.Records = append(rep.Metrics.Records, struct{ ResponseTime time.Duration }{latency})
+		}
+		rep.Metadata.TotalDuration = duration
+		return sweep.Point{Value: v, Report: rep}
+	}
+
+	testcases := []struct {
+		name   string
+		points []sweep.Point
+		exp    int
+	}{
+		{
+			name: "saturation",
+			points: []sweep.Point{
+				point(1, 10*time.Millisecond, 4*time.Second),
+				point(2, 10*time.Millisecond, 2*time.Second),
+				point(4, 11*time.Millisecond, 1*time.Second),
+				point(8, 22*time.Millisecond, 1*time.Second),
+				point(16, 44*time.Millisecond, 1*time.Second),
+			},
+			exp: 2,
+		},
+		{
+			name: "failed points are skipped",
+			points: []sweep.Point{
+				point(1, 10*time.Millisecond, 2*time.Second),
+				{Value: 2},
+				point(4, 10*time.Millisecond, 1*time.Second),
+				point(8, 40*time.Millisecond, 1*time.Second),
+			},
+			exp: 2,
+		},
+		{
+			name: "no saturation",
+			points: []sweep.Point{
+				point(1, 10*time.Millisecond, 4*time.Second),
+				point(2, 10*time.Millisecond, 2*time.Second),
+				point(4, 10*time.Millisecond, 1*time.Second),
+			},
+			exp: -1,
+		},
+		{
+			name: "noise within tolerance",
+			points: []sweep.Point{
+				point(1, 10*time.Millisecond, 1*time.Second),
+				point(2, 10500*time.Microsecond, 1*time.Second),
+			},
+			exp: -1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sweep.Knee(tc.points); got != tc.exp {
+				t.Errorf("exp %d, got %d", tc.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package testsuite
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+)
+
+// Baseline is the reference of the relative cases whose target is
+// the value of their own field in the baseline report.
+const Baseline = "baseline"
+
+// ErrRelativeCase signals an invalid relative test case.
+var ErrRelativeCase = errors.New("invalid relative test case")
+
+// RelativeCase is a test case whose target is relative to a reference
+// metric rather than an absolute value, e.g. "mean response time at
+// most 10% above baseline" or "p99 under 3× p50".
+type RelativeCase struct {
+	Name      string
+	Field     runner.MetricsField
+	Predicate runner.TestPredicate
+
+	// RelativeTo is the reference metric: Baseline for the value of Field
+	// in the baseline report, or another field of the report.
+	RelativeTo string
+
+	// Factor multiplies the reference value into the target,
+	// e.g. 1.1 for 10% above it.
+	Factor float64
+}
+
+// Validate returns an ErrRelativeCase if c cannot be evaluated: its
+// predicate is unknown, its factor is not positive, or its field and
+// reference are not metrics of a same type.
+func (c RelativeCase) Validate() error {
+	if _, ok := symbols[c.Predicate]; !ok {
+		return fmt.Errorf("%w: unknown predicate %s", ErrRelativeCase, c.Predicate)
+	}
+	if c.Factor <= 0 {
+		return fmt.Errorf("%w: factor must be positive, got %v", ErrRelativeCase, c.Factor)
+	}
+
+	typ, err := stats.FieldType(c.Field)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRelativeCase, err)
+	}
+	if c.RelativeTo == Baseline {
+		return nil
+	}
+	refType, err := stats.FieldType(runner.MetricsField(c.RelativeTo))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRelativeCase, err)
+	}
+	if refType != typ {
+		return fmt.Errorf("%w: cannot compare %s (%s) to %s (%s)", ErrRelativeCase, c.Field, typ, c.RelativeTo, refType)
+	}
+	return nil
+}
+
+// RunRelative runs cases against the metrics returned by metric, such
+// as the ones of a report or their means over repeated runs, the ones
+// relative to the baseline against base. They fail if base is nil.
+func RunRelative(
+	cases []RelativeCase,
+	metric func(runner.MetricsField) runner.MetricsValue,
+	base *runner.Report,
+) runner.TestSuiteResults {
+	suite := runner.TestSuiteResults{Pass: true, Results: make([]runner.TestCaseResult, len(cases))}
+	for i, c := range cases {
+		suite.Results[i] = EvalRelative(c, metric, base)
+		suite.Pass = suite.Pass && suite.Results[i].Pass
+	}
+	return suite
+}
+
+// EvalRelative returns the result of c against the metrics returned
+// by metric, and base if it is relative to the baseline. Its input target
+// is the value computed from the reference: a duration, or a float64
+// for counts.
+func EvalRelative(
+	c RelativeCase,
+	metric func(runner.MetricsField) runner.MetricsValue,
+	base *runner.Report,
+) runner.TestCaseResult {
+	got := metric(c.Field)
+	result := runner.TestCaseResult{
+		Input: runner.TestCase{Name: c.Name, Field: c.Field, Predicate: c.Predicate},
+		Got:   got,
+	}
+
+	refMetric, refField := metric, runner.MetricsField(c.RelativeTo)
+	if c.RelativeTo == Baseline {
+		if base == nil {
+			result.Summary = fmt.Sprintf("want %s %s %s, got no baseline report", c.Field, Symbol(c.Predicate), c.reference())
+			return result
+		}
+		refMetric = func(field runner.MetricsField) runner.MetricsValue { return stats.Metric(base, field) }
+		refField = c.Field
+	}
+
+	ref, _ := stats.Float(refMetric(refField))
+	gotValue, _ := stats.Float(got)
+	target := ref * c.Factor
+
+	result.Input.Target = target
+	if _, isDuration := got.(time.Duration); isDuration {
+		result.Input.Target = time.Duration(target)
+	}
+	result.Pass = Match(c.Predicate, floatSign(gotValue-target))
+	result.Summary = fmt.Sprintf(
+		"want %s %s %s (%v), got %v",
+		c.Field, Symbol(c.Predicate), c.reference(), result.Input.Target, got,
+	)
+	return result
+}
+
+// Merge returns the results of the suites as a single one, that passes
+// if both pass.
+func Merge(a, b runner.TestSuiteResults) runner.TestSuiteResults {
+	results := make([]runner.TestCaseResult, 0, len(a.Results)+len(b.Results))
+	return runner.TestSuiteResults{
+		Pass:    a.Pass && b.Pass,
+		Results: append(append(results, a.Results...), b.Results...),
+	}
+}
+
+// reference describes the target of c, e.g. "1.1 × baseline".
+func (c RelativeCase) reference() string {
+	if c.Factor == 1 {
+		return c.RelativeTo
+	}
+	return strconv.FormatFloat(c.Factor, 'g', -1, 64) + " × " + c.RelativeTo
+}
+
+func floatSign(v float64) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
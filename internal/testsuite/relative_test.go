@@ -0,0 +1,140 @@
+package testsuite_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/stats"
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+func TestRelativeCase_Validate(t *testing.T) {
+	valid := []testsuite.RelativeCase{
+		{Field: "ResponseTimes.Mean", Predicate: "LTE", RelativeTo: testsuite.Baseline, Factor: 1.1},
+		{Field: "ResponseTimes.P99", Predicate: "LT", RelativeTo: "ResponseTimes.P50", Factor: 3},
+		{Field: "RequestFailureCount", Predicate: "LTE", RelativeTo: "RequestCount", Factor: 0.01},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%+v: %v", c, err)
+		}
+	}
+
+	invalid := []testsuite.RelativeCase{
+		{Field: "ResponseTimes.Mean", Predicate: "NOPE", RelativeTo: testsuite.Baseline, Factor: 1},
+		{Field: "ResponseTimes.Mean", Predicate: "LT", RelativeTo: testsuite.Baseline, Factor: 0},
+		{Field: "ResponseTimes.Nope", Predicate: "LT", RelativeTo: testsuite.Baseline, Factor: 1},
+		{Field: "ResponseTimes.Mean", Predicate: "LT", RelativeTo: "ResponseTimes.Nope", Factor: 1},
+		{Field: "ResponseTimes.Mean", Predicate: "LT", RelativeTo: "RequestCount", Factor: 1},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); !errors.Is(err, testsuite.ErrRelativeCase) {
+			t.Errorf("%+v: exp ErrRelativeCase, got %v", c, err)
+		}
+	}
+}
+
+func TestRunRelative(t *testing.T) {
+	rep, base := &runner.Report{}, &runner.Report{}
+	for i := 1; i <= 100; i++ {
+		rep.Metrics.Records = append(rep.Metrics.Records, struct{ ResponseTime time.Duration }{time.Duration(i) * time.Millisecond})
+	}
+	rep.Metrics.ResponseTimes.Mean = 50 * time.Millisecond
+	base.Metrics.ResponseTimes.Mean = 40 * time.Millisecond
+	metric := func(field runner.MetricsField) runner.MetricsValue { return stats.Metric(rep, field) }
+
+	testcases := []struct {
+		name       string
+		c          testsuite.RelativeCase
+		base       *runner.Report
+		pass       bool
+		expSummary string
+	}{
+		{
+			name:       "above baseline tolerance",
+			c:          testsuite.RelativeCase{Field: "ResponseTimes.Mean", Predicate: "LTE", RelativeTo: testsuite.Baseline, Factor: 1.1},
+			base:       base,
+			pass:       false,
+			expSummary: "want ResponseTimes.Mean <= 1.1 × baseline (44ms), got 50ms",
+		},
+		{
+			name:       "within baseline tolerance",
+			c:          testsuite.RelativeCase{Field: "ResponseTimes.Mean", Predicate: "LTE", RelativeTo: testsuite.Baseline, Factor: 1.25},
+			base:       base,
+			pass:       true,
+			expSummary: "want ResponseTimes.Mean <= 1.25 × baseline (50ms), got 50ms",
+		},
+		{
+			name:       "without baseline",
+			c:          testsuite.RelativeCase{Field: "ResponseTimes.Mean", Predicate: "LTE", RelativeTo: testsuite.Baseline, Factor: 1},
+			pass:       false,
+			expSummary: "want ResponseTimes.Mean <= baseline, got no baseline report",
+		},
+		{
+			name:       "relative to another metric",
+			c:          testsuite.RelativeCase{Field: "ResponseTimes.P99", Predicate: "LT", RelativeTo: "ResponseTimes.P50", Factor: 3},
+			pass:       true,
+			expSummary: "want ResponseTimes.P99 < 3 × ResponseTimes.P50 (150ms), got 99ms",
+		},
+		{
+			name:       "counts",
+			c:          testsuite.RelativeCase{Field: "RequestFailureCount", Predicate: "LTE", RelativeTo: "RequestCount", Factor: 0.01},
+			pass:       true,
+			expSummary: "want RequestFailureCount <= 0.01 × RequestCount (1), got 0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := testsuite.RunRelative([]testsuite.RelativeCase{tc.c}, metric, tc.base)
+			if suite.Pass != tc.pass || suite.Results[0].Pass != tc.pass {
+				t.Errorf("exp pass %v, got %v", tc.pass, suite.Pass)
+			}
+			if got := suite.Results[0].Summary; got != tc.expSummary {
+				t.Errorf("\nexp %s\ngot %s", tc.expSummary, got)
+			}
+		})
+	}
+}
+
+func TestRunRelative_repeated(t *testing.T) {
+	rep := func(p50, p99 time.Duration) *runner.Report {
+		r := &runner.Report{}
+		for i := 0; i < 100; i++ {
+			d := p50
+			if i >= 98 {
+				d = p99
+			}
+			r.Metrics.Records = append(r.Metrics.Records, struct{ ResponseTime time.Duration }{d})
+		}
+		return r
+	}
+	runs := stats.Runs{rep(10*time.Millisecond, 20*time.Millisecond), rep(10*time.Millisecond, 60*time.Millisecond)}
+
+	// the p99 of the last run is 6 × p50, their mean 4 × p50
+	c := testsuite.RelativeCase{Field: "ResponseTimes.P99", Predicate: "LTE", RelativeTo: "ResponseTimes.P50", Factor: 5}
+	suite := testsuite.RunRelative([]testsuite.RelativeCase{c}, runs.Mean, nil)
+	if !suite.Pass {
+		t.Errorf("exp pass against the means of the runs, got %s", suite.Results[0].Summary)
+	}
+
+	last := func(field runner.MetricsField) runner.MetricsValue { return stats.Metric(runs[1], field) }
+	if suite := testsuite.RunRelative([]testsuite.RelativeCase{c}, last, nil); suite.Pass {
+		t.Errorf("exp fail against the last run, got %s", suite.Results[0].Summary)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	pass := runner.TestSuiteResults{Pass: true, Results: []runner.TestCaseResult{{Pass: true}}}
+	fail := runner.TestSuiteResults{Pass: false, Results: []runner.TestCaseResult{{Pass: false}}}
+
+	if got := testsuite.Merge(pass, runner.TestSuiteResults{Pass: true}); !got.Pass || len(got.Results) != 1 {
+		t.Errorf("exp passing suite of 1 result, got %+v", got)
+	}
+	if got := testsuite.Merge(pass, fail); got.Pass || len(got.Results) != 2 || !got.Results[0].Pass {
+		t.Errorf("exp failing suite of 2 results in order, got %+v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package testsuite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// ErrSeverity signals an unknown test severity.
+var ErrSeverity = errors.New("invalid test severity")
+
+// Severity is the severity of a test case: it decides whether its
+// failure fails the suite.
+type Severity string
+
+const (
+	// SeverityError is the default severity: a failing test case
+	// fails the suite.
+	SeverityError Severity = "error"
+
+	// SeverityWarn is the severity of aspirational test cases: their
+	// failures are reported, but do not fail the suite.
+	SeverityWarn Severity = "warn"
+)
+
+// ParseSeverity returns the Severity of name s, or an ErrSeverity
+// if there is none.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarn:
+		return sev, nil
+	}
+	return "", fmt.Errorf("%w: %q, want %q or %q", ErrSeverity, s, SeverityWarn, SeverityError)
+}
+
+// Warnings are the names of the test cases of severity SeverityWarn.
+type Warnings []string
+
+// Has returns true if the test case of the given name is a warning.
+func (w Warnings) Has(name string) bool {
+	for _, warning := range w {
+		if warning == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns suite with its pass decided by its results, ignoring
+// the failures of the warnings.
+func (w Warnings) Apply(suite runner.TestSuiteResults) runner.TestSuiteResults {
+	suite.Pass = true
+	for _, result := range suite.Results {
+		if !result.Pass && !w.Has(result.Input.Name) {
+			suite.Pass = false
+		}
+	}
+	return suite
+}
+
+// Count returns the numbers of passing test cases of suite, of failing
+// ones, and of failing warnings, which are not counted as failing.
+func (w Warnings) Count(suite runner.TestSuiteResults) (passed, failed, warned int) {
+	for _, result := range suite.Results {
+		switch {
+		case result.Pass:
+			passed++
+		case w.Has(result.Input.Name):
+			warned++
+		default:
+			failed++
+		}
+	}
+	return
+}
@@ -0,0 +1,50 @@
+package testsuite_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []string{"warn", "error"} {
+		if got, err := testsuite.ParseSeverity(s); err != nil || string(got) != s {
+			t.Errorf("%s: exp %s, got %s, %v", s, s, got, err)
+		}
+	}
+	for _, s := range []string{"", "WARN", "info"} {
+		if _, err := testsuite.ParseSeverity(s); !errors.Is(err, testsuite.ErrSeverity) {
+			t.Errorf("%q: exp ErrSeverity, got %v", s, err)
+		}
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	suite := runner.TestSuiteResults{Pass: false, Results: []runner.TestCaseResult{
+		{Input: runner.TestCase{Name: "pass"}, Pass: true},
+		{Input: runner.TestCase{Name: "aspirational"}, Pass: false},
+		{Input: runner.TestCase{Name: "fail"}, Pass: false},
+	}}
+
+	t.Run("failing warnings do not fail the suite", func(t *testing.T) {
+		if got := (testsuite.Warnings{"aspirational"}).Apply(suite); got.Pass {
+			t.Error("exp failing suite")
+		}
+		if got := (testsuite.Warnings{"aspirational", "fail"}).Apply(suite); !got.Pass || len(got.Results) != 3 {
+			t.Errorf("exp passing suite of 3 results, got %+v", got)
+		}
+		if got := testsuite.Warnings(nil).Apply(runner.TestSuiteResults{}); !got.Pass {
+			t.Error("exp passing empty suite")
+		}
+	})
+
+	t.Run("count failing warnings separately", func(t *testing.T) {
+		passed, failed, warned := (testsuite.Warnings{"aspirational", "pass"}).Count(suite)
+		if passed != 1 || failed != 1 || warned != 1 {
+			t.Errorf("exp 1 passed, 1 failed, 1 warned, got %d, %d, %d", passed, failed, warned)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+// Package testsuite runs test cases against metrics computed by the CLI,
+// such as the means of repeated runs, the way the engine runs them
+// against the metrics of a single run.
+package testsuite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+)
+
+// symbols maps the predicates to their symbol in test summaries.
+var symbols = map[runner.TestPredicate]string{
+	"EQ":  "==",
+	"NEQ": "!=",
+	"GT":  ">",
+	"GTE": ">=",
+	"LT":  "<",
+	"LTE": "<=",
+}
+
+// Run runs cases, the metric of their field being returned by metric.
+func Run(cases []runner.TestCase, metric func(runner.MetricsField) runner.MetricsValue) runner.TestSuiteResults {
+	suite := runner.TestSuiteResults{Pass: true, Results: make([]runner.TestCaseResult, len(cases))}
+	for i, c := range cases {
+		suite.Results[i] = Eval(c, metric(c.Field))
+		suite.Pass = suite.Pass && suite.Results[i].Pass
+	}
+	return suite
+}
+
+// Eval returns the result of c, got being the value of its metric.
+// Values of mismatching or unsupported types never pass.
+func Eval(c runner.TestCase, got runner.MetricsValue) runner.TestCaseResult {
+	result := runner.TestCaseResult{
+		Input: c,
+		Got:   got,
+		Summary: fmt.Sprintf(
+			"want %s %s %v, got %v",
+			c.Field, Symbol(c.Predicate), c.Target, got,
+		),
+	}
+
+	cmp, ok := compare(got, c.Target)
+	if !ok {
+		result.Summary = fmt.Sprintf("cannot compare %s: %v (%T) and %v (%T)", c.Field, got, got, c.Target, c.Target)
+		return result
+	}
+	result.Pass = Match(c.Predicate, cmp)
+	return result
+}
+
+// Symbol returns the symbol of predicate p, e.g. "<=" for LTE.
+func Symbol(p runner.TestPredicate) string {
+	if s, ok := symbols[p]; ok {
+		return s
+	}
+	return "unknown predicate"
+}
+
+// Match returns true if a comparison result cmp, negative if the value
+// is lower than the target, positive if greater, satisfies predicate p.
+func Match(p runner.TestPredicate, cmp int) bool {
+	switch p {
+	case "EQ":
+		return cmp == 0
+	case "NEQ":
+		return cmp != 0
+	case "GT":
+		return cmp > 0
+	case "GTE":
+		return cmp >= 0
+	case "LT":
+		return cmp < 0
+	case "LTE":
+		return cmp <= 0
+	}
+	return false
+}
+
+// compare compares got to target, both ints or both durations.
+func compare(got, target runner.MetricsValue) (int, bool) {
+	switch got := got.(type) {
+	case int:
+		if target, ok := target.(int); ok {
+			return sign(int64(got) - int64(target)), true
+		}
+	case time.Duration:
+		if target, ok := target.(time.Duration); ok {
+			return sign(int64(got - target)), true
+		}
+	}
+	return 0, false
+}
+
+func sign(v int64) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package testsuite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benchttp/engine/runner"
+
+	"github.com/benchttp/cli/internal/testsuite"
+)
+
+func TestRun(t *testing.T) {
+	metrics := map[runner.MetricsField]runner.MetricsValue{
+		"ResponseTimes.Mean":  100 * time.Millisecond,
+		"RequestFailureCount": 2,
+	}
+	metric := func(field runner.MetricsField) runner.MetricsValue {
+		return metrics[field]
+	}
+
+	testcases := []struct {
+		name string
+		c    runner.TestCase
+		pass bool
+	}{
+		{name: "duration LTE", c: runner.TestCase{Field: "ResponseTimes.Mean", Predicate: "LTE", Target: 100 * time.Millisecond}, pass: true},
+		{name: "duration LT", c: runner.TestCase{Field: "ResponseTimes.Mean", Predicate: "LT", Target: 100 * time.Millisecond}, pass: false},
+		{name: "int EQ", c: runner.TestCase{Field: "RequestFailureCount", Predicate: "EQ", Target: 2}, pass: true},
+		{name: "int NEQ", c: runner.TestCase{Field: "RequestFailureCount", Predicate: "NEQ", Target: 2}, pass: false},
+		{name: "int GT", c: runner.TestCase{Field: "RequestFailureCount", Predicate: "GT", Target: 1}, pass: true},
+		{name: "int GTE", c: runner.TestCase{Field: "RequestFailureCount", Predicate: "GTE", Target: 3}, pass: false},
+		{name: "mismatching types", c: runner.TestCase{Field: "RequestFailureCount", Predicate: "EQ", Target: 2 * time.Nanosecond}, pass: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			suite := testsuite.Run([]runner.TestCase{tc.c}, metric)
+			if suite.Pass != tc.pass || suite.Results[0].Pass != tc.pass {
+				t.Errorf("exp pass %v, got %v: %s", tc.pass, suite.Pass, suite.Results[0].Summary)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	c := runner.TestCase{Name: "mean", Field: "ResponseTimes.Mean", Predicate: "LTE", Target: 50 * time.Millisecond}
+
+	got := testsuite.Eval(c, 60*time.Millisecond)
+	if got.Pass {
+		t.Error("exp fail")
+	}
+	if exp := "want ResponseTimes.Mean <= 50ms, got 60ms"; got.Summary != exp {
+		t.Errorf("exp summary %q, got %q", exp, got.Summary)
+	}
+	if got.Input.Name != "mean" || got.Got != 60*time.Millisecond {
+		t.Errorf("bad result: %+v", got)
+	}
+}